@@ -359,14 +359,14 @@ func (d *viewModeDropdown) HTML() (h.HTML, error) {
 						Inner: &h.A{
 							Inner:  h.String(viewModeOptions[rellenv.Canvas]),
 							Target: "_top",
-							HREF:   d.Env.CanvasURL(d.Example.URL),
+							HREF:   d.Env.CanvasURLString(d.Example.URL),
 						},
 					},
 					&h.Li{
 						Inner: &h.A{
 							Inner:  h.String(viewModeOptions[rellenv.PageTab]),
 							Target: "_top",
-							HREF:   d.Env.PageTabURL(d.Example.URL),
+							HREF:   d.Env.PageTabURLString(d.Example.URL),
 						},
 					},
 				},
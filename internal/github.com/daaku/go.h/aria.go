@@ -0,0 +1,54 @@
+package h
+
+// ARIAAttributes are the aria-* attribute names, without the prefix, defined
+// by the WAI-ARIA 1.2 specification.
+var ARIAAttributes = map[string]bool{
+	"activedescendant": true,
+	"atomic":           true,
+	"autocomplete":     true,
+	"busy":             true,
+	"checked":          true,
+	"colcount":         true,
+	"colindex":         true,
+	"colspan":          true,
+	"controls":         true,
+	"current":          true,
+	"describedby":      true,
+	"details":          true,
+	"disabled":         true,
+	"dropeffect":       true,
+	"errormessage":     true,
+	"expanded":         true,
+	"flowto":           true,
+	"grabbed":          true,
+	"haspopup":         true,
+	"hidden":           true,
+	"invalid":          true,
+	"keyshortcuts":     true,
+	"label":            true,
+	"labelledby":       true,
+	"level":            true,
+	"live":             true,
+	"modal":            true,
+	"multiline":        true,
+	"multiselectable":  true,
+	"orientation":      true,
+	"owns":             true,
+	"placeholder":      true,
+	"posinset":         true,
+	"pressed":          true,
+	"readonly":         true,
+	"relevant":         true,
+	"required":         true,
+	"roledescription":  true,
+	"rowcount":         true,
+	"rowindex":         true,
+	"rowspan":          true,
+	"selected":         true,
+	"setsize":          true,
+	"sort":             true,
+	"valuemax":         true,
+	"valuemin":         true,
+	"valuenow":         true,
+	"valuetext":        true,
+}
@@ -5,10 +5,99 @@ import (
 	"html"
 	"io"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 )
 
-type Attributes map[string]interface{}
+// Attr is a single attribute key value pair.
+type Attr struct {
+	Key   string
+	Value string
+}
+
+// Attributes are an ordered list of attributes. Use Set to add or replace
+// entries to avoid duplicate keys.
+type Attributes []Attr
+
+// Set updates the value for an existing key, or appends a new entry.
+func (attrs *Attributes) Set(key, value string) {
+	for i := range *attrs {
+		if (*attrs)[i].Key == key {
+			(*attrs)[i].Value = value
+			return
+		}
+	}
+	*attrs = append(*attrs, Attr{Key: key, Value: value})
+}
+
+// Get returns the value for the key, or an empty string if it isn't set.
+func (attrs Attributes) Get(key string) string {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Class merges the given class names into the class attribute.
+func (attrs *Attributes) Class(classes ...string) *Attributes {
+	existing := strings.Fields(attrs.Get("class"))
+Outer:
+	for _, c := range classes {
+		for _, e := range existing {
+			if c == e {
+				continue Outer
+			}
+		}
+		if c != "" {
+			existing = append(existing, c)
+		}
+	}
+	attrs.Set("class", strings.Join(existing, " "))
+	return attrs
+}
+
+// Data sets a data-* attribute.
+func (attrs *Attributes) Data(key, value string) *Attributes {
+	attrs.Set("data-"+key, value)
+	return attrs
+}
+
+// Merge returns a new Attributes with the entries from other replacing those
+// with the same key, and new keys appended. Classes are combined instead of
+// replaced.
+func (attrs Attributes) Merge(other Attributes) Attributes {
+	merged := append(Attributes(nil), attrs...)
+	for _, a := range other {
+		if a.Key == "class" {
+			merged.Class(strings.Fields(a.Value)...)
+			continue
+		}
+		merged.Set(a.Key, a.Value)
+	}
+	return merged
+}
+
+// String formats the attributes as space separated key="value" pairs, for
+// debugging and logging.
+func (attrs Attributes) String() string {
+	parts := make([]string, len(attrs))
+	for i, a := range attrs {
+		parts[i] = fmt.Sprintf(`%s="%s"`, a.Key, EscapeAttr(a.Value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// GoString formats the attributes as Go code for use with %#v.
+func (attrs Attributes) GoString() string {
+	parts := make([]string, len(attrs))
+	for i, a := range attrs {
+		parts[i] = fmt.Sprintf("{Key: %q, Value: %q}", a.Key, a.Value)
+	}
+	return "h.Attributes{" + strings.Join(parts, ", ") + "}"
+}
 
 // Render an attribute value.
 func writeValue(w io.Writer, i interface{}) (int, error) {
@@ -29,7 +118,13 @@ func writeValue(w io.Writer, i interface{}) (int, error) {
 		return 0, fmt.Errorf(
 			`Could not write attribute value "%v" with kind %s`, i, value.Kind())
 	}
-	return fmt.Fprint(w, html.EscapeString(res))
+	return fmt.Fprint(w, EscapeAttr(res))
+}
+
+// EscapeAttr escapes a value for use inside a single or double quoted
+// attribute. Both quote characters are escaped, along with <, > and &.
+func EscapeAttr(s string) string {
+	return html.EscapeString(s)
 }
 
 // Check if a value is empty.
@@ -95,8 +190,27 @@ func writeKeyValue(w io.Writer, key string, val interface{}) (int, error) {
 func (attrs Attributes) Write(w io.Writer, prefix string) (int, error) {
 	var written, i int
 	var err error
-	for key, val := range attrs {
-		i, err = writeKeyValue(w, prefix+key, val)
+	for _, a := range attrs {
+		i, err = writeKeyValue(w, prefix+a.Key, a.Value)
+		written += i
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// Render a map of attributes in sorted key order using the key prefix.
+func writeDict(w io.Writer, prefix string, dict map[string]interface{}) (int, error) {
+	keys := make([]string, 0, len(dict))
+	for key := range dict {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var written, i int
+	var err error
+	for _, key := range keys {
+		i, err = writeKeyValue(w, prefix+key, dict[key])
 		written += i
 		if err != nil {
 			return written, err
@@ -0,0 +1,108 @@
+package h_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestAttributesSetDeduplicates(t *testing.T) {
+	t.Parallel()
+	attrs := h.Attributes{}
+	attrs.Set("id", "a")
+	attrs.Set("title", "b")
+	attrs.Set("id", "c")
+	assertRender(t, &h.Node{Tag: "p", Attributes: attrs}, `<p id="c" title="b"></p>`)
+}
+
+func TestAttributesStableOrder(t *testing.T) {
+	t.Parallel()
+	attrs := h.Attributes{{"z", "1"}, {"a", "2"}, {"m", "3"}}
+	for i := 0; i < 10; i++ {
+		assertRender(t, &h.Node{Tag: "p", Attributes: attrs}, `<p z="1" a="2" m="3"></p>`)
+	}
+}
+
+func TestAttributesClass(t *testing.T) {
+	t.Parallel()
+	attrs := h.Attributes{}
+	attrs.Class("btn", "").Class("btn", "primary").Data("id", "42")
+	assertRender(t, &h.Node{Tag: "p", Attributes: attrs},
+		`<p class="btn primary" data-id="42"></p>`)
+}
+
+func TestDictAttributesSorted(t *testing.T) {
+	t.Parallel()
+	div := &h.Div{Data: map[string]interface{}{"b": 2, "a": "x"}}
+	assertRender(t, div, `<div data-a="x" data-b="2"></div>`)
+}
+
+func TestEscapeAttr(t *testing.T) {
+	t.Parallel()
+	const expected = `&lt;a href=&#39;x&#39; title=&#34;y&#34;&gt;`
+	if actual := h.EscapeAttr(`<a href='x' title="y">`); actual != expected {
+		t.Fatalf("was expecting %q but got %q", expected, actual)
+	}
+}
+
+func TestAttributeValuesEscaped(t *testing.T) {
+	t.Parallel()
+	node := &h.Node{
+		Tag: "img",
+		Attributes: h.Attributes{
+			{Key: "alt", Value: `<script>alert(1)</script>`},
+			{Key: "title", Value: `" onload="evil()`},
+		},
+	}
+	assertRender(t, node, `<img alt="&lt;script&gt;alert(1)&lt;/script&gt;"`+
+		` title="&#34; onload=&#34;evil()">`)
+}
+
+func TestAttributesString(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		attrs    h.Attributes
+		expected string
+	}{
+		{nil, ``},
+		{h.Attributes{{Key: "title", Value: `a "b" <c>`}},
+			`title="a &#34;b&#34; &lt;c&gt;"`},
+		{h.Attributes{{Key: "href", Value: "/"}, {Key: "class", Value: "x"}},
+			`href="/" class="x"`},
+	}
+	for _, c := range cases {
+		if actual := c.attrs.String(); actual != c.expected {
+			t.Fatalf("was expecting %q but got %q", c.expected, actual)
+		}
+	}
+}
+
+func TestAttributesGoString(t *testing.T) {
+	t.Parallel()
+	attrs := h.Attributes{{Key: "href", Value: "/"}, {Key: "title", Value: `"x"`}}
+	const expected = `h.Attributes{{Key: "href", Value: "/"}, {Key: "title", Value: "\"x\""}}`
+	if actual := fmt.Sprintf("%#v", attrs); actual != expected {
+		t.Fatalf("was expecting %s but got %s", expected, actual)
+	}
+	if actual := fmt.Sprintf("%#v", h.Attributes{}); actual != "h.Attributes{}" {
+		t.Fatalf("unexpected empty GoString %s", actual)
+	}
+}
+
+func TestAttributesMerge(t *testing.T) {
+	t.Parallel()
+	base := h.Attributes{
+		{Key: "class", Value: "btn"},
+		{Key: "type", Value: "button"},
+	}
+	merged := base.Merge(h.Attributes{
+		{Key: "type", Value: "submit"},
+		{Key: "class", Value: "primary btn"},
+		{Key: "id", Value: "go"},
+	})
+	assertRender(t, &h.Node{Tag: "button", Attributes: merged},
+		`<button class="btn primary" type="submit" id="go"></button>`)
+	assertRender(t, &h.Node{Tag: "button", Attributes: base},
+		`<button class="btn" type="button"></button>`)
+}
@@ -0,0 +1,55 @@
+package h
+
+// Builder incrementally constructs a Node.
+type Builder struct {
+	tag      string
+	attrs    Attributes
+	children Fragment
+}
+
+// Tag sets the tag name.
+func (b *Builder) Tag(tag string) *Builder {
+	b.tag = tag
+	return b
+}
+
+// Attr sets an attribute.
+func (b *Builder) Attr(key, value string) *Builder {
+	b.attrs.Set(key, value)
+	return b
+}
+
+// Class adds classes to the class attribute.
+func (b *Builder) Class(classes ...string) *Builder {
+	b.attrs.Class(classes...)
+	return b
+}
+
+// Add appends children.
+func (b *Builder) Add(children ...HTML) *Builder {
+	b.children = append(b.children, children...)
+	return b
+}
+
+// AddIf appends the child if cond is true.
+func (b *Builder) AddIf(cond bool, child HTML) *Builder {
+	if cond {
+		b.children = append(b.children, child)
+	}
+	return b
+}
+
+// Build returns the Node. It panics if Tag was not called.
+func (b *Builder) Build() *Node {
+	if b.tag == "" {
+		panic("h: Builder.Build called without a Tag")
+	}
+	n := &Node{Tag: b.tag}
+	if b.attrs != nil {
+		n.Attributes = append(Attributes(nil), b.attrs...)
+	}
+	if b.children != nil {
+		n.Inner = append(Fragment(nil), b.children...)
+	}
+	return n
+}
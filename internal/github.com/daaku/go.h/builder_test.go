@@ -0,0 +1,36 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+	node := new(h.Builder).
+		Tag("ul").
+		Attr("id", "nav").
+		Class("menu").
+		Class("open", "menu").
+		Add(h.String("a"), h.String("b")).
+		AddIf(false, h.String("hidden")).
+		AddIf(true, h.String("c")).
+		Build()
+	assertRender(t, node, `<ul id="nav" class="menu open">abc</ul>`)
+}
+
+func TestBuilderEmpty(t *testing.T) {
+	t.Parallel()
+	assertRender(t, new(h.Builder).Tag("br").Build(), `<br>`)
+}
+
+func TestBuilderWithoutTag(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("was expecting a panic")
+		}
+	}()
+	new(h.Builder).Add(h.String("x")).Build()
+}
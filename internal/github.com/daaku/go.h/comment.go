@@ -0,0 +1,46 @@
+package h
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type comment string
+
+// Comment returns a HTML comment. Any "--" in the text is written as "- -" to
+// avoid terminating the comment early.
+func Comment(text string) HTML {
+	return comment(text)
+}
+
+func (c comment) HTML() (HTML, error) {
+	return c, fmt.Errorf("comment.HTML called for %s", string(c))
+}
+
+func (c comment) Write(w io.Writer) (int, error) {
+	text := string(c)
+	for strings.Contains(text, "--") {
+		text = strings.Replace(text, "--", "- -", -1)
+	}
+	return fmt.Fprint(w, "<!-- ", text, " -->")
+}
+
+type conditionalComment struct {
+	condition string
+	inner     string
+}
+
+// ConditionalComment returns an IE style conditional comment such as
+// <!--[if lt IE 9]>...<![endif]-->. The inner markup is not escaped.
+func ConditionalComment(condition, inner string) HTML {
+	return &conditionalComment{condition: condition, inner: inner}
+}
+
+func (c *conditionalComment) HTML() (HTML, error) {
+	return c, fmt.Errorf("conditionalComment.HTML called for %s", c.condition)
+}
+
+func (c *conditionalComment) Write(w io.Writer) (int, error) {
+	return fmt.Fprint(w, "<!--[if ", c.condition, "]>", c.inner, "<![endif]-->")
+}
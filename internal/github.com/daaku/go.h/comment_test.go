@@ -0,0 +1,24 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestComment(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.Comment("hello"), `<!-- hello -->`)
+}
+
+func TestCommentEscapesDashes(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.Comment("a -- b --> c"), `<!-- a - - b - -> c -->`)
+	assertRender(t, h.Comment("---"), `<!-- - - - -->`)
+}
+
+func TestConditionalComment(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.ConditionalComment("lt IE 9", `<script src="a.js"></script>`),
+		`<!--[if lt IE 9]><script src="a.js"></script><![endif]-->`)
+}
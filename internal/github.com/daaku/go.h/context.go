@@ -0,0 +1,136 @@
+package h
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+// HTMLCtx is HTML that needs request scoped data from the context.
+type HTMLCtx interface {
+	HTMLWithContext(ctx context.Context) (HTML, error)
+}
+
+// ContextualHTML is both HTML and HTMLCtx, so it can be nested in other nodes
+// and also written using WriteContext.
+type ContextualHTML interface {
+	HTML
+	HTMLCtx
+}
+
+type contextual func(ctx context.Context) HTML
+
+// Contextual adapts a function to ContextualHTML. When written without
+// WriteContext it receives a background context.
+func Contextual(fn func(ctx context.Context) HTML) ContextualHTML {
+	return contextual(fn)
+}
+
+func (c contextual) HTMLWithContext(ctx context.Context) (HTML, error) {
+	return c(ctx), nil
+}
+
+func (c contextual) HTML() (HTML, error) {
+	return c(context.Background()), nil
+}
+
+// Write HTML into a writer, providing the context to any HTMLCtx found in the
+// tree, including those nested inside Nodes and fragments. The output is
+// minified if the context was created using WithMinification.
+func WriteContext(ctx context.Context, w io.Writer, h HTMLCtx) (int, error) {
+	html, err := h.HTMLWithContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !minifyEnabled(ctx) {
+		cw := &contextWriter{ctx: ctx, w: w}
+		return cw.write(html)
+	}
+	mw := &MinifyWriter{W: w}
+	cw := &contextWriter{ctx: ctx, w: mw}
+	written, err := cw.write(html)
+	if err != nil {
+		return written, err
+	}
+	return written, mw.Flush()
+}
+
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (c *contextWriter) write(h HTML) (int, error) {
+	var err error
+	for {
+		switch t := h.(type) {
+		case nil:
+			return 0, nil
+		case HTMLCtx:
+			h, err = t.HTMLWithContext(c.ctx)
+			if err != nil {
+				return 0, err
+			}
+		case *Node:
+			return c.writeNode(t, []HTML{t.Inner})
+		case *ReflectNode:
+			inner, err := t.inner()
+			if err != nil {
+				return 0, err
+			}
+			return c.writeNode(t, inner)
+		case *Frag:
+			return c.writeChildren(*t)
+		case Fragment:
+			return c.writeChildren(t)
+		case Primitive:
+			return t.Write(c.w)
+		case HTML:
+			h, err = h.HTML()
+			if err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("Value %+v of unknown type %T", h, h)
+		}
+	}
+}
+
+func (c *contextWriter) writeNode(n openCloser, inner []HTML) (int, error) {
+	written := 0
+	i := 0
+	var err error
+
+	i, err = n.writeOpen(c.w)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = c.writeChildren(inner)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = n.writeClose(c.w)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
+
+func (c *contextWriter) writeChildren(children []HTML) (int, error) {
+	written := 0
+	for _, e := range children {
+		i, err := c.write(e)
+		written += i
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
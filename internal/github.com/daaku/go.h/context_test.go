@@ -0,0 +1,42 @@
+package h_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+type userKeyT int
+
+const userKey userKeyT = 0
+
+var userName = h.Contextual(func(ctx context.Context) h.HTML {
+	name, _ := ctx.Value(userKey).(string)
+	return h.String(name)
+})
+
+func TestWriteContextNested(t *testing.T) {
+	t.Parallel()
+	ctx := context.WithValue(context.Background(), userKey, "naitik")
+	page := h.Contextual(func(ctx context.Context) h.HTML {
+		return &h.Div{Inner: &h.Frag{
+			&h.Node{Tag: "p", Inner: userName},
+			&h.Span{Inner: userName},
+		}}
+	})
+	var buf bytes.Buffer
+	if _, err := h.WriteContext(ctx, &buf, page); err != nil {
+		t.Fatal(err)
+	}
+	const expected = `<div><p>naitik</p><span>naitik</span></div>`
+	if buf.String() != expected {
+		t.Fatalf("Did not find expected:\n%s\ninstead found:\n%s", expected, buf.String())
+	}
+}
+
+func TestContextualWithoutContext(t *testing.T) {
+	t.Parallel()
+	assertRender(t, &h.P{Inner: userName}, `<p></p>`)
+}
@@ -0,0 +1,67 @@
+package h
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// CSSClass is a list of CSS class names. The methods return a new CSSClass
+// and leave the receiver unchanged.
+type CSSClass []string
+
+func (c CSSClass) has(class string) bool {
+	for _, e := range c {
+		if e == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Add returns a CSSClass with the given classes added.
+func (c CSSClass) Add(classes ...string) CSSClass {
+	result := append(CSSClass(nil), c...)
+	for _, class := range classes {
+		if class != "" && !result.has(class) {
+			result = append(result, class)
+		}
+	}
+	return result
+}
+
+// Remove returns a CSSClass without the given class.
+func (c CSSClass) Remove(class string) CSSClass {
+	var result CSSClass
+	for _, e := range c {
+		if e != class {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// Toggle returns a CSSClass with the given class added if on is true, or
+// removed otherwise.
+func (c CSSClass) Toggle(class string, on bool) CSSClass {
+	if on {
+		return c.Add(class)
+	}
+	return c.Remove(class)
+}
+
+// String returns the space separated classes, without duplicates or empty
+// strings.
+func (c CSSClass) String() string {
+	return strings.Join(CSSClass(nil).Add(c...), " ")
+}
+
+func (c CSSClass) HTML() (HTML, error) {
+	return c, fmt.Errorf("CSSClass.HTML called for %s", c)
+}
+
+// Write the escaped class string.
+func (c CSSClass) Write(w io.Writer) (int, error) {
+	return fmt.Fprint(w, html.EscapeString(c.String()))
+}
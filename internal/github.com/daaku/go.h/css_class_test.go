@@ -0,0 +1,43 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestCSSClass(t *testing.T) {
+	t.Parallel()
+	base := h.CSSClass{"btn"}
+	c := base.Add("btn-primary", "", "btn").Toggle("active", true).Toggle("btn-primary", false)
+	if c.String() != "btn active" {
+		t.Fatalf("unexpected classes %q", c.String())
+	}
+	if base.String() != "btn" {
+		t.Fatalf("base was modified: %q", base.String())
+	}
+	if c.Remove("btn").Remove("missing").String() != "active" {
+		t.Fatalf("unexpected classes after remove %q", c.Remove("btn").String())
+	}
+}
+
+func TestCSSClassStringFilters(t *testing.T) {
+	t.Parallel()
+	c := h.CSSClass{"", "a", "b", "a", ""}
+	if c.String() != "a b" {
+		t.Fatalf("unexpected classes %q", c.String())
+	}
+	if h.CSSClass(nil).String() != "" {
+		t.Fatal("was expecting an empty string")
+	}
+}
+
+func TestCSSClassAttr(t *testing.T) {
+	t.Parallel()
+	c := h.CSSClass{"a"}.Add("b")
+	assertRender(t, &h.Node{
+		Tag:        "div",
+		Attributes: h.Attributes{{Key: "class", Value: c.String()}},
+		Inner:      c,
+	}, `<div class="a b">a b</div>`)
+}
@@ -1,11 +1,22 @@
 package h
 
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
 type XMLNS map[string]string
 
 func (ns XMLNS) Attributes() Attributes {
+	keys := make([]string, 0, len(ns))
+	for key := range ns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 	attrs := Attributes{}
-	for key, val := range ns {
-		attrs["xmlns:"+key] = val
+	for _, key := range keys {
+		attrs.Set("xmlns:"+key, ns[key])
 	}
 	return attrs
 }
@@ -20,10 +31,10 @@ type Document struct {
 func (d *Document) HTML() (HTML, error) {
 	attrs := d.XMLNS.Attributes()
 	if d.ID != "" {
-		attrs["id"] = d.ID
+		attrs.Set("id", d.ID)
 	}
 	if d.Lang != "" {
-		attrs["lang"] = "en"
+		attrs.Set("lang", "en")
 	}
 
 	return &Frag{
@@ -35,3 +46,31 @@ func (d *Document) HTML() (HTML, error) {
 		},
 	}, nil
 }
+
+// Doctype writes the HTML5 doctype declaration.
+var Doctype HTML = &doctype{}
+
+type doctype struct{}
+
+func (d *doctype) HTML() (HTML, error) {
+	return d, fmt.Errorf("doctype.HTML called")
+}
+
+func (d *doctype) Write(w io.Writer) (int, error) {
+	return io.WriteString(w, "<!DOCTYPE html>\n")
+}
+
+// Page composes a complete HTML5 page with the given title, additional head
+// content and body.
+func Page(title string, head, body HTML) HTML {
+	return Fragment{
+		Doctype,
+		&Node{
+			Tag: "html",
+			Inner: Fragment{
+				&Head{Inner: Fragment{&Title{String(title)}, head}},
+				&Body{Inner: body},
+			},
+		},
+	}
+}
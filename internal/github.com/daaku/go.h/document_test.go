@@ -1,6 +1,7 @@
 package h_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/daaku/rell/internal/github.com/daaku/go.h"
@@ -30,3 +31,17 @@ func TestDocumentLangAttr(t *testing.T) {
 	}
 	assertRender(t, doc, `<!doctype html><html lang="en"></html>`)
 }
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+	page := h.Page("a", &h.Meta{Charset: "utf-8"}, h.String("b"))
+	actual, err := h.Render(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Index(actual, "<!DOCTYPE html>") != 0 {
+		t.Fatalf("doctype not found at start of %q", actual)
+	}
+	assertRender(t, page, "<!DOCTYPE html>\n<html><head><title>a</title>"+
+		`<meta charset="utf-8"></head><body>b</body></html>`)
+}
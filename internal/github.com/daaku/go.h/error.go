@@ -0,0 +1,35 @@
+package h
+
+import (
+	"fmt"
+	"io"
+)
+
+// SuppressErrors makes Error write only the comment without returning the
+// error. It is read without synchronization while rendering, so it must only
+// be set once during program initialization, before any rendering starts, and
+// never changed afterwards.
+var SuppressErrors bool
+
+type errorHTML struct{ err error }
+
+// Error returns HTML that writes the error in a HTML comment and returns it
+// from Write, unless SuppressErrors is set.
+func Error(err error) HTML {
+	return errorHTML{err}
+}
+
+func (e errorHTML) HTML() (HTML, error) {
+	return e, fmt.Errorf("errorHTML.HTML called for %s", e.err)
+}
+
+func (e errorHTML) Write(w io.Writer) (int, error) {
+	written, err := comment("render error: " + e.err.Error()).Write(w)
+	if err != nil {
+		return written, err
+	}
+	if SuppressErrors {
+		return written, nil
+	}
+	return written, e.err
+}
@@ -0,0 +1,31 @@
+package h_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestError(t *testing.T) {
+	t.Parallel()
+	expected := errors.New("fail -- now")
+	var buf bytes.Buffer
+	_, err := h.Write(&buf, &h.Div{Inner: h.Error(expected)})
+	if err != expected {
+		t.Fatalf("was expecting %v but got %v", expected, err)
+	}
+	const out = `<div><!-- render error: fail - - now -->`
+	if buf.String() != out {
+		t.Fatalf("was expecting %q but got %q", out, buf.String())
+	}
+}
+
+// Not parallel since it changes a package level variable.
+func TestErrorSuppressed(t *testing.T) {
+	h.SuppressErrors = true
+	defer func() { h.SuppressErrors = false }()
+	assertRender(t, &h.Div{Inner: h.Error(errors.New("fail"))},
+		`<div><!-- render error: fail --></div>`)
+}
@@ -0,0 +1,167 @@
+package h
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Elements that are written on a single line by WriteFormatted. This includes
+// elements where whitespace is significant.
+var inlineElements = map[string]bool{
+	"a":        true,
+	"abbr":     true,
+	"b":        true,
+	"bdi":      true,
+	"bdo":      true,
+	"cite":     true,
+	"code":     true,
+	"dfn":      true,
+	"em":       true,
+	"i":        true,
+	"kbd":      true,
+	"label":    true,
+	"mark":     true,
+	"pre":      true,
+	"q":        true,
+	"s":        true,
+	"samp":     true,
+	"script":   true,
+	"small":    true,
+	"span":     true,
+	"strong":   true,
+	"style":    true,
+	"sub":      true,
+	"sup":      true,
+	"textarea": true,
+	"time":     true,
+	"title":    true,
+	"u":        true,
+	"var":      true,
+}
+
+// Write HTML into a writer with each node on its own line, indented by its
+// nesting depth. Inline elements are written on a single line. This is meant
+// for debugging as it changes the whitespace in the document.
+func WriteFormatted(w io.Writer, h HTML, indent string) (int, error) {
+	f := &formatter{w: w, indent: indent}
+	return f.write(h, 0)
+}
+
+type formatter struct {
+	w      io.Writer
+	indent string
+}
+
+func (f *formatter) write(h HTML, depth int) (int, error) {
+	var err error
+	for {
+		switch t := h.(type) {
+		case nil:
+			return 0, nil
+		case *Node:
+			return f.writeNode(t.Tag, t.selfClosing(), t, []HTML{t.Inner}, depth)
+		case *ReflectNode:
+			inner, err := t.inner()
+			if err != nil {
+				return 0, err
+			}
+			return f.writeNode(t.Tag, t.SelfClosing, t, inner, depth)
+		case *Frag:
+			return f.writeChildren(*t, depth)
+		case Fragment:
+			return f.writeChildren(t, depth)
+		case Primitive:
+			return f.writeLine(t, depth)
+		case HTML:
+			h, err = h.HTML()
+			if err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("Value %+v of unknown type %T", h, h)
+		}
+	}
+}
+
+type openCloser interface {
+	Primitive
+	writeOpen(io.Writer) (int, error)
+	writeClose(io.Writer) (int, error)
+}
+
+func (f *formatter) writeNode(tag string, selfClosing bool, n openCloser, inner []HTML, depth int) (int, error) {
+	if inlineElements[tag] {
+		return f.writeLine(n, depth)
+	}
+
+	written := 0
+	i := 0
+	var err error
+
+	i, err = f.writeLine(primitiveFunc(n.writeOpen), depth)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = f.writeChildren(inner, depth+1)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	if !selfClosing {
+		i, err = f.writeLine(primitiveFunc(n.writeClose), depth)
+		written += i
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+func (f *formatter) writeChildren(children []HTML, depth int) (int, error) {
+	written := 0
+	for _, c := range children {
+		i, err := f.write(c, depth)
+		written += i
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+func (f *formatter) writeLine(p Primitive, depth int) (int, error) {
+	written := 0
+	i := 0
+	var err error
+
+	i, err = io.WriteString(f.w, strings.Repeat(f.indent, depth))
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = p.Write(f.w)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = io.WriteString(f.w, "\n")
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
+
+type primitiveFunc func(io.Writer) (int, error)
+
+func (p primitiveFunc) Write(w io.Writer) (int, error) {
+	return p(w)
+}
@@ -0,0 +1,27 @@
+package h_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestWriteFormatted(t *testing.T) {
+	t.Parallel()
+	html := &h.Div{
+		Inner: &h.Frag{
+			&h.P{Inner: &h.Span{Inner: h.String("a")}},
+			&h.Node{Tag: "br"},
+			h.String("b"),
+		},
+	}
+	var buf bytes.Buffer
+	if _, err := h.WriteFormatted(&buf, html, "  "); err != nil {
+		t.Fatal(err)
+	}
+	const expected = "<div>\n  <p>\n    <span>a</span>\n  </p>\n  <br>\n  b\n</div>\n"
+	if buf.String() != expected {
+		t.Fatalf("Did not find expected:\n%s\ninstead found:\n%s", expected, buf.String())
+	}
+}
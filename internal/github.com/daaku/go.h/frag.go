@@ -26,3 +26,23 @@ func (f *Frag) Write(w io.Writer) (int, error) {
 	}
 	return written, nil
 }
+
+// Fragment is a value variant of Frag for rendering sibling nodes without a
+// wrapping element. A nil Fragment writes nothing.
+type Fragment []HTML
+
+func (f Fragment) HTML() (HTML, error) {
+	return f, fmt.Errorf("Fragment.HTML called for %s", f)
+}
+
+func (f Fragment) Write(w io.Writer) (int, error) {
+	written := 0
+	for _, e := range f {
+		i, err := Write(w, e)
+		written += i
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
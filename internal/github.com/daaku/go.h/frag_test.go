@@ -0,0 +1,17 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestFragment(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.Fragment{&h.Li{}, h.String("a"), &h.Li{}}, `<li></li>a<li></li>`)
+}
+
+func TestNilFragment(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.Fragment(nil), ``)
+}
@@ -6,7 +6,6 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"log"
 )
 
 type HTML interface {
@@ -44,11 +43,40 @@ func Render(h HTML) (string, error) {
 	return buffer.String(), err
 }
 
-// Compile static HTML into HTML. Will panic if there are errors.
-func Compile(h HTML) HTML {
+// Render HTML as bytes. The returned slice is not copied.
+func RenderBytes(h HTML) ([]byte, error) {
+	var buffer bytes.Buffer
+	_, err := Write(&buffer, h)
+	return buffer.Bytes(), err
+}
+
+// Render HTML into a writer, discarding the number of bytes written.
+func RenderTo(w io.Writer, h HTML) error {
+	_, err := Write(w, h)
+	return err
+}
+
+// Compile static HTML into HTML, returning any rendering errors.
+func CompileSafe(h HTML) (HTML, error) {
 	m, err := Render(h)
 	if err != nil {
-		log.Fatalf("Failed to Compile HTML %v with error %s", h, err)
+		return nil, err
+	}
+	return Unsafe(m), nil
+}
+
+// MustCompile static HTML into HTML. Will panic if there are errors.
+func MustCompile(h HTML) HTML {
+	m, err := CompileSafe(h)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to Compile HTML %v with error %s", h, err))
 	}
-	return Unsafe(m)
+	return m
+}
+
+// Compile static HTML into HTML. Will panic if there are errors.
+//
+// Deprecated: Use MustCompile.
+func Compile(h HTML) HTML {
+	return MustCompile(h)
 }
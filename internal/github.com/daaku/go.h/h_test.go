@@ -0,0 +1,61 @@
+package h_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestRenderBytes(t *testing.T) {
+	t.Parallel()
+	b, err := h.RenderBytes(&h.Li{Inner: h.String("a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `<li>a</li>` {
+		t.Fatalf("unexpected output %q", b)
+	}
+}
+
+func TestRenderTo(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	if err := h.RenderTo(&buf, &h.Li{Inner: h.String("a")}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `<li>a</li>` {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
+
+func TestCompileSafe(t *testing.T) {
+	t.Parallel()
+	html, err := h.CompileSafe(&h.Li{Inner: h.String("a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := html.(h.Unsafe); !ok {
+		t.Fatalf("was expecting Unsafe but got %T", html)
+	}
+	assertRender(t, html, `<li>a</li>`)
+}
+
+func TestCompileSafeError(t *testing.T) {
+	t.Parallel()
+	expected := errors.New("fail")
+	if _, err := h.CompileSafe(errHTML{expected}); err != expected {
+		t.Fatalf("was expecting error %s but got %v", expected, err)
+	}
+}
+
+func TestMustCompilePanics(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("was expecting a panic")
+		}
+	}()
+	h.MustCompile(errHTML{errors.New("fail")})
+}
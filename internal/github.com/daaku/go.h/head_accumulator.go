@@ -0,0 +1,61 @@
+package h
+
+import (
+	"sync"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+// HeadAccumulator collects CSS and JS includes from components, dropping
+// duplicates, so they can be rendered once in the head.
+type HeadAccumulator struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	includes Fragment
+}
+
+func (a *HeadAccumulator) add(key string, html HTML) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.seen == nil {
+		a.seen = make(map[string]bool)
+	}
+	if a.seen[key] {
+		return
+	}
+	a.seen[key] = true
+	a.includes = append(a.includes, html)
+}
+
+// AddCSS adds a stylesheet unless it was already added.
+func (a *HeadAccumulator) AddCSS(href string) {
+	a.add("css:"+href, ExternalStyle(href))
+}
+
+// AddJS adds a script unless it was already added.
+func (a *HeadAccumulator) AddJS(src string) {
+	a.add("js:"+src, ExternalScript(src))
+}
+
+// Flush returns the accumulated includes in insertion order.
+func (a *HeadAccumulator) Flush() HTML {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append(Fragment(nil), a.includes...)
+}
+
+type contextHeadAccumulatorKeyT int
+
+var contextHeadAccumulatorKey = contextHeadAccumulatorKeyT(1)
+
+// WithHeadAccumulator adds a new HeadAccumulator to the context.
+func WithHeadAccumulator(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextHeadAccumulatorKey, &HeadAccumulator{})
+}
+
+// HeadAccumulatorFromContext retrieves the HeadAccumulator from the context,
+// or nil if there isn't one.
+func HeadAccumulatorFromContext(ctx context.Context) *HeadAccumulator {
+	a, _ := ctx.Value(contextHeadAccumulatorKey).(*HeadAccumulator)
+	return a
+}
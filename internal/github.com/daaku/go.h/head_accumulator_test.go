@@ -0,0 +1,29 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+func TestHeadAccumulator(t *testing.T) {
+	t.Parallel()
+	ctx := h.WithHeadAccumulator(context.Background())
+	a := h.HeadAccumulatorFromContext(ctx)
+	a.AddCSS("/a.css")
+	a.AddJS("/a.js")
+	h.HeadAccumulatorFromContext(ctx).AddCSS("/a.css")
+	a.AddCSS("/b.css")
+	assertRender(t, a.Flush(),
+		`<link rel="stylesheet" type="text/css" href="/a.css">`+
+			`<script type="text/javascript" src="/a.js"></script>`+
+			`<link rel="stylesheet" type="text/css" href="/b.css">`)
+}
+
+func TestHeadAccumulatorMissing(t *testing.T) {
+	t.Parallel()
+	if h.HeadAccumulatorFromContext(context.Background()) != nil {
+		t.Fatal("was expecting nil")
+	}
+}
@@ -0,0 +1,59 @@
+package h
+
+import (
+	"strings"
+)
+
+// Attrs returns Attributes for the given list.
+func Attrs(attrs ...Attr) Attributes {
+	return Attributes(attrs)
+}
+
+func Href(url string) Attr {
+	return Attr{Key: "href", Value: url}
+}
+
+func Src(url string) Attr {
+	return Attr{Key: "src", Value: url}
+}
+
+func ID(id string) Attr {
+	return Attr{Key: "id", Value: id}
+}
+
+// Class returns a class attribute, ignoring empty class names.
+func Class(classes ...string) Attr {
+	var nonEmpty []string
+	for _, c := range classes {
+		if c != "" {
+			nonEmpty = append(nonEmpty, c)
+		}
+	}
+	return Attr{Key: "class", Value: strings.Join(nonEmpty, " ")}
+}
+
+func Alt(text string) Attr {
+	return Attr{Key: "alt", Value: text}
+}
+
+// TitleAttr returns a title attribute. It is named to avoid a conflict with
+// the Title type.
+func TitleAttr(text string) Attr {
+	return Attr{Key: "title", Value: text}
+}
+
+func Name(n string) Attr {
+	return Attr{Key: "name", Value: n}
+}
+
+func Value(v string) Attr {
+	return Attr{Key: "value", Value: v}
+}
+
+func Type(t string) Attr {
+	return Attr{Key: "type", Value: t}
+}
+
+func Rel(r string) Attr {
+	return Attr{Key: "rel", Value: r}
+}
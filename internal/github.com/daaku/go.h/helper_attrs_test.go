@@ -0,0 +1,37 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestAttrs(t *testing.T) {
+	t.Parallel()
+	node := &h.Node{
+		Tag: "a",
+		Attributes: h.Attrs(
+			h.ID("i"),
+			h.Class("a", "", "b"),
+			h.Href("/h"),
+			h.TitleAttr("t"),
+			h.Rel("r"),
+		),
+	}
+	assertRender(t, node, `<a id="i" class="a b" href="/h" title="t" rel="r"></a>`)
+}
+
+func TestInputAttrs(t *testing.T) {
+	t.Parallel()
+	node := &h.Node{
+		Tag:        "input",
+		Attributes: h.Attrs(h.Type("text"), h.Name("n"), h.Value("v")),
+	}
+	assertRender(t, node, `<input type="text" name="n" value="v">`)
+}
+
+func TestImgAttrs(t *testing.T) {
+	t.Parallel()
+	node := &h.Node{Tag: "img", Attributes: h.Attrs(h.Src("/s"), h.Alt("a"))}
+	assertRender(t, node, `<img src="/s" alt="a">`)
+}
@@ -0,0 +1,25 @@
+package h
+
+// If returns then when cond is true, and nil otherwise.
+func If(cond bool, then HTML) HTML {
+	if cond {
+		return then
+	}
+	return nil
+}
+
+// Unless returns then when cond is false, and nil otherwise.
+func Unless(cond bool, then HTML) HTML {
+	if !cond {
+		return then
+	}
+	return nil
+}
+
+// IfElse returns then when cond is true, and else_ otherwise.
+func IfElse(cond bool, then, else_ HTML) HTML {
+	if cond {
+		return then
+	}
+	return else_
+}
@@ -0,0 +1,25 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestIf(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.If(true, h.String("a")), `a`)
+	assertRender(t, h.If(false, h.String("a")), ``)
+}
+
+func TestUnless(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.Unless(true, h.String("a")), ``)
+	assertRender(t, h.Unless(false, h.String("a")), `a`)
+}
+
+func TestIfElse(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.IfElse(true, h.String("a"), h.String("b")), `a`)
+	assertRender(t, h.IfElse(false, h.String("a"), h.String("b")), `b`)
+}
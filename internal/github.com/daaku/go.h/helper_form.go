@@ -0,0 +1,34 @@
+package h
+
+// NewForm returns a Form with the given method and action.
+func NewForm(method, action string, inner HTML) HTML {
+	return &Form{Method: method, Action: action, Inner: inner}
+}
+
+// InputText returns a text input.
+func InputText(name, value, placeholder string) HTML {
+	return &Input{Type: "text", Name: name, Value: value, Placeholder: placeholder}
+}
+
+// InputHidden returns a hidden input.
+func InputHidden(name, value string) HTML {
+	return &Input{Type: "hidden", Name: name, Value: value}
+}
+
+// NewButton returns a Button of the given type, defaulting to "submit".
+func NewButton(type_ string, inner HTML) HTML {
+	if type_ == "" {
+		type_ = "submit"
+	}
+	return &Button{Type: type_, Inner: inner}
+}
+
+// NewSelect returns a Select with the given options.
+func NewSelect(name string, options HTML) HTML {
+	return &Select{Name: name, Inner: options}
+}
+
+// NewOption returns an Option with an escaped label.
+func NewOption(value, label string, selected bool) HTML {
+	return &Option{Value: value, Selected: selected, Inner: String(label)}
+}
@@ -0,0 +1,30 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestNewForm(t *testing.T) {
+	t.Parallel()
+	form := h.NewForm(h.Post, "/save", h.Fragment{
+		h.InputText("q", "v", "Search"),
+		h.InputHidden("x", "1"),
+		h.NewButton("", h.String("Go")),
+	})
+	assertRender(t, form, `<form action="/save" method="post">`+
+		`<input name="q" type="text" value="v" placeholder="Search">`+
+		`<input name="x" type="hidden" value="1">`+
+		`<button type="submit">Go</button></form>`)
+}
+
+func TestNewSelect(t *testing.T) {
+	t.Parallel()
+	sel := h.NewSelect("s", h.Fragment{
+		h.NewOption("a", "A & B", false),
+		h.NewOption("b", "B", true),
+	})
+	assertRender(t, sel, `<select name="s"><option value="a">A &amp; B</option>`+
+		`<option value="b" selected>B</option></select>`)
+}
@@ -0,0 +1,49 @@
+package h
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// H returns a heading of the given level, which must be between 1 and 6.
+func H(level int, inner HTML, attrs ...Attr) HTML {
+	if level < 1 || level > 6 {
+		return Error(fmt.Errorf("Invalid heading level %d", level))
+	}
+	return &Node{
+		Tag:        "h" + strconv.Itoa(level),
+		Attributes: Attributes(attrs),
+		Inner:      inner,
+	}
+}
+
+// NewH1 through NewH6 return headings of the respective level. They are named
+// to avoid a conflict with the H1 and H2 types.
+func NewH1(inner HTML, attrs ...Attr) HTML { return H(1, inner, attrs...) }
+func NewH2(inner HTML, attrs ...Attr) HTML { return H(2, inner, attrs...) }
+func NewH3(inner HTML, attrs ...Attr) HTML { return H(3, inner, attrs...) }
+func NewH4(inner HTML, attrs ...Attr) HTML { return H(4, inner, attrs...) }
+func NewH5(inner HTML, attrs ...Attr) HTML { return H(5, inner, attrs...) }
+func NewH6(inner HTML, attrs ...Attr) HTML { return H(6, inner, attrs...) }
+
+// Slug returns a URL safe version of the text: lowercased, with spaces
+// replaced by hyphens and other non alphanumeric characters removed.
+func Slug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+// HeadingWithID returns a heading of the given level with the text, and the
+// id set to the Slug of the text.
+func HeadingWithID(level int, text string) HTML {
+	return H(level, String(text), ID(Slug(text)))
+}
@@ -0,0 +1,36 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestHeadings(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.NewH1(h.String("a")), `<h1>a</h1>`)
+	assertRender(t, h.NewH2(h.String("a"), h.Class("x")), `<h2 class="x">a</h2>`)
+	assertRender(t, h.NewH3(h.String("a")), `<h3>a</h3>`)
+	assertRender(t, h.NewH4(h.String("a")), `<h4>a</h4>`)
+	assertRender(t, h.NewH5(h.String("a")), `<h5>a</h5>`)
+	assertRender(t, h.NewH6(h.String("a")), `<h6>a</h6>`)
+	assertRender(t, h.H(3, h.String("b"), h.ID("c")), `<h3 id="c">b</h3>`)
+}
+
+func TestHeadingInvalidLevel(t *testing.T) {
+	t.Parallel()
+	for _, level := range []int{0, 7, -1} {
+		if _, err := h.Render(h.H(level, nil)); err == nil {
+			t.Fatalf("was expecting an error for level %d", level)
+		}
+		if _, err := h.Render(h.HeadingWithID(level, "x")); err == nil {
+			t.Fatalf("was expecting an error for level %d", level)
+		}
+	}
+}
+
+func TestHeadingWithID(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.HeadingWithID(2, "Hello, World & Friends 2"),
+		`<h2 id="hello-world--friends-2">Hello, World &amp; Friends 2</h2>`)
+}
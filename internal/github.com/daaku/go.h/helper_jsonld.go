@@ -0,0 +1,19 @@
+package h
+
+import "encoding/json"
+
+// JSONLD returns a script element with v as JSON-LD structured data. The JSON
+// is not HTML escaped, since entities are not decoded inside script elements.
+// json.Marshal writes <, > and & as \u003c, \u003e and \u0026, so the data
+// cannot close the script element.
+func JSONLD(v interface{}) (HTML, error) {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{
+		Tag:        "script",
+		Attributes: Attributes{{Key: "type", Value: "application/ld+json"}},
+		Inner:      UnsafeBytes(j),
+	}, nil
+}
@@ -0,0 +1,39 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestJSONLD(t *testing.T) {
+	t.Parallel()
+	html, err := h.JSONLD(map[string]string{
+		"@context": "https://schema.org",
+		"name":     `A & B <c> "d"`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRender(t, html, `<script type="application/ld+json">`+
+		`{"@context":"https://schema.org","name":"A \u0026 B \u003cc\u003e \"d\""}`+
+		`</script>`)
+}
+
+func TestJSONLDScriptBreakout(t *testing.T) {
+	t.Parallel()
+	html, err := h.JSONLD([]string{"</script><script>alert(1)</script>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRender(t, html, `<script type="application/ld+json">`+
+		`["\u003c/script\u003e\u003cscript\u003ealert(1)\u003c/script\u003e"]`+
+		`</script>`)
+}
+
+func TestJSONLDError(t *testing.T) {
+	t.Parallel()
+	if _, err := h.JSONLD(make(chan int)); err == nil {
+		t.Fatal("was expecting an error")
+	}
+}
@@ -0,0 +1,63 @@
+package h
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Schemes allowed in an href. The http and https schemes also require a host.
+var hrefSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+	"tel":    true,
+}
+
+// Check that href is a relative URL or an absolute URL with an allowed scheme.
+func validHref(href string) error {
+	if href == "" {
+		return errors.New("Empty href")
+	}
+	u, err := url.Parse(href)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() {
+		return nil
+	}
+	if !hrefSchemes[u.Scheme] {
+		return fmt.Errorf("Disallowed URL scheme %q in %q", u.Scheme, href)
+	}
+	if (u.Scheme == "http" || u.Scheme == "https") && u.Host == "" {
+		return fmt.Errorf("Invalid absolute URL %q", href)
+	}
+	return nil
+}
+
+// NewA returns a link to href. An invalid href results in an error when
+// rendered. It is named to avoid a conflict with the A type.
+func NewA(href string, inner HTML) HTML {
+	if err := validHref(href); err != nil {
+		return Error(err)
+	}
+	return &Node{Tag: "a", Attributes: Attrs(Href(href)), Inner: inner}
+}
+
+// NewImg returns an image. A missing alt results in an error when rendered.
+// It is named to avoid a conflict with the Img type.
+func NewImg(src, alt string) HTML {
+	if alt == "" {
+		return Error(fmt.Errorf("Missing alt for image %q", src))
+	}
+	return &Node{Tag: "img", Attributes: Attrs(Src(src), Alt(alt))}
+}
+
+// NewLink returns a link element. It is named to avoid a conflict with the
+// Link type.
+func NewLink(rel, href string) HTML {
+	if err := validHref(href); err != nil {
+		return Error(err)
+	}
+	return &Node{Tag: "link", Attributes: Attrs(Rel(rel), Href(href))}
+}
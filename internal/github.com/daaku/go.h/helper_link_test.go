@@ -0,0 +1,36 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestNewA(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.NewA("/foo?a=1&b=2", h.String("x")), `<a href="/foo?a=1&amp;b=2">x</a>`)
+	assertRender(t, h.NewA("https://example.com/", nil), `<a href="https://example.com/"></a>`)
+	assertRender(t, h.NewA("mailto:a@example.com", nil), `<a href="mailto:a@example.com"></a>`)
+	assertRender(t, h.NewA("tel:+15555550100", nil), `<a href="tel:+15555550100"></a>`)
+	for _, href := range []string{"", "javascript:alert(1)", "data:text/html,x", "http:foo", "http://%zz"} {
+		if _, err := h.Render(h.NewA(href, nil)); err == nil {
+			t.Fatalf("was expecting an error for href %q", href)
+		}
+	}
+}
+
+func TestNewImg(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.NewImg("/a.png", "A"), `<img src="/a.png" alt="A">`)
+	if _, err := h.Render(h.NewImg("/a.png", "")); err == nil {
+		t.Fatal("was expecting an error for a missing alt")
+	}
+}
+
+func TestNewLink(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.NewLink("stylesheet", "/a.css"), `<link rel="stylesheet" href="/a.css">`)
+	if _, err := h.Render(h.NewLink("stylesheet", "")); err == nil {
+		t.Fatal("was expecting an error for an empty href")
+	}
+}
@@ -0,0 +1,33 @@
+package h
+
+func listItems(items []HTML) Fragment {
+	lis := make(Fragment, len(items))
+	for i, item := range items {
+		lis[i] = &Node{Tag: "li", Inner: item}
+	}
+	return lis
+}
+
+// UL returns an unordered list with each item wrapped in a li.
+func UL(items ...HTML) HTML {
+	return &Node{Tag: "ul", Inner: listItems(items)}
+}
+
+// OL returns an ordered list with each item wrapped in a li.
+func OL(items ...HTML) HTML {
+	return &Node{Tag: "ol", Inner: listItems(items)}
+}
+
+// DL returns a definition list from term and description pairs. The first,
+// third and so on items are wrapped in a dt, the others in a dd.
+func DL(pairs ...HTML) HTML {
+	inner := make(Fragment, len(pairs))
+	for i, item := range pairs {
+		tag := "dd"
+		if i%2 == 0 {
+			tag = "dt"
+		}
+		inner[i] = &Node{Tag: tag, Inner: item}
+	}
+	return &Node{Tag: "dl", Inner: inner}
+}
@@ -0,0 +1,29 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestListsEmpty(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.UL(), `<ul></ul>`)
+	assertRender(t, h.OL(), `<ol></ol>`)
+	assertRender(t, h.DL(), `<dl></dl>`)
+}
+
+func TestListsSingle(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.UL(h.String("a")), `<ul><li>a</li></ul>`)
+	assertRender(t, h.OL(h.String("a")), `<ol><li>a</li></ol>`)
+	assertRender(t, h.DL(h.String("a")), `<dl><dt>a</dt></dl>`)
+}
+
+func TestListsThree(t *testing.T) {
+	t.Parallel()
+	a, b, c := h.String("a"), h.String("b"), h.String("c")
+	assertRender(t, h.UL(a, b, c), `<ul><li>a</li><li>b</li><li>c</li></ul>`)
+	assertRender(t, h.OL(a, b, c), `<ol><li>a</li><li>b</li><li>c</li></ol>`)
+	assertRender(t, h.DL(a, b, c), `<dl><dt>a</dt><dd>b</dd><dt>c</dt></dl>`)
+}
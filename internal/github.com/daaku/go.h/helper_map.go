@@ -0,0 +1,19 @@
+package h
+
+// Map renders each item using fn, in order.
+func Map[T any](items []T, fn func(T) HTML) HTML {
+	f := make(Fragment, len(items))
+	for i, item := range items {
+		f[i] = fn(item)
+	}
+	return f
+}
+
+// MapWithIndex renders each item using fn, in order, also providing the index.
+func MapWithIndex[T any](items []T, fn func(int, T) HTML) HTML {
+	f := make(Fragment, len(items))
+	for i, item := range items {
+		f[i] = fn(i, item)
+	}
+	return f
+}
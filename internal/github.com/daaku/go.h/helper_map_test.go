@@ -0,0 +1,49 @@
+package h_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+type errHTML struct{ err error }
+
+func (e errHTML) HTML() (h.HTML, error) {
+	return nil, e.err
+}
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+	html := h.Map([]string{"a", "b"}, func(s string) h.HTML {
+		return &h.Li{Inner: h.String(s)}
+	})
+	assertRender(t, html, `<li>a</li><li>b</li>`)
+}
+
+func TestMapWithIndex(t *testing.T) {
+	t.Parallel()
+	html := h.MapWithIndex([]string{"a", "b"}, func(i int, s string) h.HTML {
+		return h.String(strconv.Itoa(i) + s)
+	})
+	assertRender(t, html, `0a1b`)
+}
+
+func TestMapStopsOnError(t *testing.T) {
+	t.Parallel()
+	expected := errors.New("fail")
+	html := h.Map([]int{1, 2, 3}, func(i int) h.HTML {
+		if i == 2 {
+			return errHTML{expected}
+		}
+		return h.String(strconv.Itoa(i))
+	})
+	actual, err := h.Render(html)
+	if err != expected {
+		t.Fatalf("was expecting error %s but got %v", expected, err)
+	}
+	if actual != "1" {
+		t.Fatalf(`was expecting "1" but got %q`, actual)
+	}
+}
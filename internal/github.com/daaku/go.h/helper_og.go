@@ -0,0 +1,42 @@
+package h
+
+import "sort"
+
+// OGTags returns Open Graph meta tags for the properties, sorted by key. The
+// keys are given without the og: prefix.
+func OGTags(props map[string]string) HTML {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tags := make(Fragment, len(keys))
+	for i, key := range keys {
+		tags[i] = ogTag(key, props[key])
+	}
+	return tags
+}
+
+func ogTag(key, value string) HTML {
+	return &Meta{Property: "og:" + key, Content: value}
+}
+
+// OGTitle returns the og:title meta tag.
+func OGTitle(t string) HTML {
+	return ogTag("title", t)
+}
+
+// OGDescription returns the og:description meta tag.
+func OGDescription(d string) HTML {
+	return ogTag("description", d)
+}
+
+// OGImage returns the og:image meta tag for the image URL.
+func OGImage(url string) HTML {
+	return ogTag("image", url)
+}
+
+// OGType returns the og:type meta tag, such as "website".
+func OGType(t string) HTML {
+	return ogTag("type", t)
+}
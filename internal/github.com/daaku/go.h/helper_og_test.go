@@ -0,0 +1,25 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestOGTags(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.OGTags(map[string]string{
+		"url":   "https://example.com/?a=1&b=2",
+		"title": "Rell",
+	}), `<meta property="og:title" content="Rell">`+
+		`<meta property="og:url" content="https://example.com/?a=1&amp;b=2">`)
+	assertRender(t, h.OGTags(nil), ``)
+}
+
+func TestOGHelpers(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.OGTitle("T"), `<meta property="og:title" content="T">`)
+	assertRender(t, h.OGDescription("D"), `<meta property="og:description" content="D">`)
+	assertRender(t, h.OGImage("/i.png"), `<meta property="og:image" content="/i.png">`)
+	assertRender(t, h.OGType("website"), `<meta property="og:type" content="website">`)
+}
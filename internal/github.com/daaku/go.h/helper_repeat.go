@@ -0,0 +1,13 @@
+package h
+
+// Repeat renders fn for each index from 0 to n-1, in order.
+func Repeat(n int, fn func(i int) HTML) HTML {
+	if n <= 0 {
+		return Fragment(nil)
+	}
+	f := make(Fragment, n)
+	for i := range f {
+		f[i] = fn(i)
+	}
+	return f
+}
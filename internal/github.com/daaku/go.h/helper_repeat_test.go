@@ -0,0 +1,25 @@
+package h_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestRepeat(t *testing.T) {
+	t.Parallel()
+	html := h.Repeat(3, func(i int) h.HTML {
+		return &h.Li{Inner: h.String(strconv.Itoa(i))}
+	})
+	assertRender(t, html, `<li>0</li><li>1</li><li>2</li>`)
+}
+
+func TestRepeatZero(t *testing.T) {
+	t.Parallel()
+	html := h.Repeat(0, func(i int) h.HTML {
+		t.Fatal("was not expecting a call")
+		return nil
+	})
+	assertRender(t, html, ``)
+}
@@ -0,0 +1,43 @@
+package h
+
+// ExternalScript returns a script element loading the given src. It is named
+// to avoid a conflict with the Script type.
+func ExternalScript(src string) HTML {
+	return &Node{
+		Tag:        "script",
+		Attributes: Attributes{{Key: "type", Value: "text/javascript"}, {Key: "src", Value: src}},
+	}
+}
+
+// InlineScript returns a script element with the given JavaScript. The
+// JavaScript is not escaped.
+func InlineScript(js string) HTML {
+	return &Node{
+		Tag:        "script",
+		Attributes: Attributes{{Key: "type", Value: "text/javascript"}},
+		Inner:      Unsafe(js),
+	}
+}
+
+// ExternalStyle returns a link element loading the given stylesheet. It is
+// named to avoid a conflict with the Style type.
+func ExternalStyle(href string) HTML {
+	return &Node{
+		Tag: "link",
+		Attributes: Attributes{
+			{Key: "rel", Value: "stylesheet"},
+			{Key: "type", Value: "text/css"},
+			{Key: "href", Value: href},
+		},
+	}
+}
+
+// InlineStyle returns a style element with the given CSS. The CSS is not
+// escaped.
+func InlineStyle(css string) HTML {
+	return &Node{
+		Tag:        "style",
+		Attributes: Attributes{{Key: "type", Value: "text/css"}},
+		Inner:      Unsafe(css),
+	}
+}
@@ -0,0 +1,30 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestExternalScript(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.ExternalScript("/a.js"),
+		`<script type="text/javascript" src="/a.js"></script>`)
+}
+
+func TestInlineScript(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.InlineScript("if (a < b) {}"),
+		`<script type="text/javascript">if (a < b) {}</script>`)
+}
+
+func TestExternalStyle(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.ExternalStyle("/a.css"),
+		`<link rel="stylesheet" type="text/css" href="/a.css">`)
+}
+
+func TestInlineStyle(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.InlineStyle("a > b {}"), `<style type="text/css">a > b {}</style>`)
+}
@@ -0,0 +1,55 @@
+package h
+
+// NewTable returns a table with the given header and body rows. The thead is
+// omitted if header is nil.
+func NewTable(attrs Attributes, header, body HTML) HTML {
+	var thead HTML
+	if header != nil {
+		thead = &Node{Tag: "thead", Inner: header}
+	}
+	return &Node{
+		Tag:        "table",
+		Attributes: attrs,
+		Inner: Fragment{
+			thead,
+			&Node{Tag: "tbody", Inner: body},
+		},
+	}
+}
+
+// TR returns a table row containing the given cells.
+func TR(cells ...HTML) HTML {
+	return &Node{Tag: "tr", Inner: Fragment(cells)}
+}
+
+// TH returns a table header cell.
+func TH(inner HTML) HTML {
+	return &Node{Tag: "th", Inner: inner}
+}
+
+// TD returns a table data cell.
+func TD(inner HTML) HTML {
+	return &Node{Tag: "td", Inner: inner}
+}
+
+// TableOf returns a complete table with the given headers and rows. Each
+// cell in a row is wrapped in a td.
+func TableOf(headers []string, rows [][]HTML) HTML {
+	var header HTML
+	if len(headers) > 0 {
+		cells := make([]HTML, len(headers))
+		for i, h := range headers {
+			cells[i] = TH(String(h))
+		}
+		header = TR(cells...)
+	}
+	body := make(Fragment, len(rows))
+	for i, row := range rows {
+		cells := make([]HTML, len(row))
+		for j, cell := range row {
+			cells[j] = TD(cell)
+		}
+		body[i] = TR(cells...)
+	}
+	return NewTable(nil, header, body)
+}
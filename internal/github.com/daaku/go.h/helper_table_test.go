@@ -0,0 +1,29 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestTableOf(t *testing.T) {
+	t.Parallel()
+	table := h.TableOf(
+		[]string{"a", "b"},
+		[][]h.HTML{{h.String("1"), h.String("2")}},
+	)
+	assertRender(t, table, `<table><thead><tr><th>a</th><th>b</th></tr></thead>`+
+		`<tbody><tr><td>1</td><td>2</td></tr></tbody></table>`)
+}
+
+func TestTableOfEmpty(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.TableOf(nil, nil), `<table><tbody></tbody></table>`)
+}
+
+func TestNewTable(t *testing.T) {
+	t.Parallel()
+	table := h.NewTable(h.Attrs(h.Class("grid")), h.TR(h.TH(h.String("a"))), nil)
+	assertRender(t, table, `<table class="grid"><thead><tr><th>a</th></tr></thead>`+
+		`<tbody></tbody></table>`)
+}
@@ -3,6 +3,7 @@
 package h
 
 import (
+	"bytes"
 	"log"
 	"net/http"
 )
@@ -18,3 +19,19 @@ func WriteResponse(w http.ResponseWriter, r *http.Request, html HTML) {
 		}
 	}
 }
+
+// Writes a HTML response with the given status code. The HTML is rendered
+// before anything is sent, so a rendering error results in a 500 response
+// and the error is returned.
+func RespondHTML(w http.ResponseWriter, status int, html HTML) error {
+	var buf bytes.Buffer
+	if _, err := Write(&buf, html); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError)
+		return err
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := buf.WriteTo(w)
+	return err
+}
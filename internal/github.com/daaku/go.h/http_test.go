@@ -0,0 +1,42 @@
+//go:build !js
+// +build !js
+
+package h_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestRespondHTML(t *testing.T) {
+	t.Parallel()
+	w := httptest.NewRecorder()
+	if err := h.RespondHTML(w, http.StatusCreated, h.String("a")); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusCreated {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if w.Body.String() != "a" {
+		t.Fatalf("unexpected body %q", w.Body.String())
+	}
+}
+
+func TestRespondHTMLError(t *testing.T) {
+	t.Parallel()
+	expected := errors.New("fail")
+	w := httptest.NewRecorder()
+	if err := h.RespondHTML(w, http.StatusOK, errHTML{expected}); err != expected {
+		t.Fatalf("was expecting error %s but got %v", expected, err)
+	}
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+}
@@ -0,0 +1,24 @@
+package h
+
+type lazy func() HTML
+
+// Lazy defers building the HTML until it is rendered.
+func Lazy(fn func() HTML) HTML {
+	return lazy(fn)
+}
+
+func (l lazy) HTML() (HTML, error) {
+	return l(), nil
+}
+
+type lazyErr func() (HTML, error)
+
+// LazyErr defers building the HTML until it is rendered, returning any error
+// from fn as a rendering error.
+func LazyErr(fn func() (HTML, error)) HTML {
+	return lazyErr(fn)
+}
+
+func (l lazyErr) HTML() (HTML, error) {
+	return l()
+}
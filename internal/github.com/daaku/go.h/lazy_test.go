@@ -0,0 +1,34 @@
+package h_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+	called := false
+	html := h.Lazy(func() h.HTML {
+		called = true
+		return h.String("a")
+	})
+	assertRender(t, h.If(false, html), ``)
+	if called {
+		t.Fatal("was not expecting a call")
+	}
+	assertRender(t, html, `a`)
+	if !called {
+		t.Fatal("was expecting a call")
+	}
+}
+
+func TestLazyErr(t *testing.T) {
+	t.Parallel()
+	expected := errors.New("fail")
+	html := h.LazyErr(func() (h.HTML, error) { return nil, expected })
+	if _, err := h.Render(html); err != expected {
+		t.Fatalf("was expecting error %s but got %v", expected, err)
+	}
+}
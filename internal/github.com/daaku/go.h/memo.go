@@ -0,0 +1,39 @@
+package h
+
+import (
+	"sync"
+)
+
+var memoCache sync.Map
+
+type memo struct {
+	key  string
+	html HTML
+}
+
+// Memo renders the HTML once and reuses the result on subsequent renders of
+// any Memo with the same key. Keys must be unique across the application, as
+// the cache is shared. Rendering errors are returned and not cached.
+func Memo(key string, h HTML) HTML {
+	return &memo{key: key, html: h}
+}
+
+func (m *memo) HTML() (HTML, error) {
+	if cached, ok := memoCache.Load(m.key); ok {
+		return cached.(Unsafe), nil
+	}
+	result, err := Render(m.html)
+	if err != nil {
+		return nil, err
+	}
+	actual, _ := memoCache.LoadOrStore(m.key, Unsafe(result))
+	return actual.(Unsafe), nil
+}
+
+// ClearMemo removes all cached values. This is useful for tests.
+func ClearMemo() {
+	memoCache.Range(func(key, value interface{}) bool {
+		memoCache.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,29 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+type countHTML struct{ count *int }
+
+func (c countHTML) HTML() (h.HTML, error) {
+	*c.count++
+	return h.String("a"), nil
+}
+
+func TestMemo(t *testing.T) {
+	defer h.ClearMemo()
+	count := 0
+	assertRender(t, h.Memo("memo-test", countHTML{&count}), `a`)
+	assertRender(t, h.Memo("memo-test", countHTML{&count}), `a`)
+	if count != 1 {
+		t.Fatalf("was expecting a single render but got %d", count)
+	}
+	h.ClearMemo()
+	assertRender(t, h.Memo("memo-test", countHTML{&count}), `a`)
+	if count != 2 {
+		t.Fatalf("was expecting a render after clear but got %d", count)
+	}
+}
@@ -0,0 +1,67 @@
+package h
+
+import (
+	"io"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+// MinifyWriter strips runs of whitespace between a '>' and a '<'. Whitespace
+// is held until the next non whitespace byte is seen, so Flush must be called
+// once writing is complete. Note that this also applies inside elements where
+// whitespace is significant, like pre.
+type MinifyWriter struct {
+	W        io.Writer
+	afterTag bool
+	pending  []byte
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+func (m *MinifyWriter) Write(p []byte) (int, error) {
+	out := make([]byte, 0, len(p))
+	for _, b := range p {
+		if m.afterTag && isSpace(b) {
+			m.pending = append(m.pending, b)
+			continue
+		}
+		if len(m.pending) > 0 {
+			if b != '<' {
+				out = append(out, m.pending...)
+			}
+			m.pending = m.pending[:0]
+		}
+		out = append(out, b)
+		m.afterTag = b == '>'
+	}
+	if _, err := m.W.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any held whitespace.
+func (m *MinifyWriter) Flush() error {
+	if len(m.pending) == 0 {
+		return nil
+	}
+	_, err := m.W.Write(m.pending)
+	m.pending = m.pending[:0]
+	return err
+}
+
+type contextMinifyKeyT int
+
+var contextMinifyKey = contextMinifyKeyT(1)
+
+// WithMinification enables minification of output from WriteContext.
+func WithMinification(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextMinifyKey, true)
+}
+
+func minifyEnabled(ctx context.Context) bool {
+	minify, _ := ctx.Value(contextMinifyKey).(bool)
+	return minify
+}
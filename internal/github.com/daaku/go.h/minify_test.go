@@ -0,0 +1,43 @@
+package h_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+func TestWriteContextMinified(t *testing.T) {
+	t.Parallel()
+	page := h.Contextual(func(ctx context.Context) h.HTML {
+		return &h.Div{Inner: h.Fragment{
+			h.Unsafe("\n  <p>a b</p>\n  "),
+			h.Unsafe("\t<p> c </p>\n"),
+		}}
+	})
+	var buf bytes.Buffer
+	ctx := h.WithMinification(context.Background())
+	if _, err := h.WriteContext(ctx, &buf, page); err != nil {
+		t.Fatal(err)
+	}
+	const expected = `<div><p>a b</p><p> c </p></div>`
+	if buf.String() != expected {
+		t.Fatalf("Did not find expected:\n%q\ninstead found:\n%q", expected, buf.String())
+	}
+}
+
+func TestMinifyWriterFlush(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	mw := &h.MinifyWriter{W: &buf}
+	mw.Write([]byte("<b>  "))
+	mw.Write([]byte("  x"))
+	mw.Write([]byte("<i>  "))
+	if err := mw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "<b>    x<i>  " {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
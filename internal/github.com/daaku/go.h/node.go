@@ -3,13 +3,102 @@ package h
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 )
 
+// VoidElements are the HTML5 elements that never have a closing tag. Nodes
+// with these tags are written as self closing unless ForceClose is set.
+var VoidElements = map[string]bool{
+	"area":   true,
+	"base":   true,
+	"br":     true,
+	"col":    true,
+	"embed":  true,
+	"hr":     true,
+	"img":    true,
+	"input":  true,
+	"link":   true,
+	"meta":   true,
+	"param":  true,
+	"source": true,
+	"track":  true,
+	"wbr":    true,
+}
+
 type Node struct {
 	Tag         string
 	Attributes  Attributes
 	Inner       HTML
 	SelfClosing bool
+	ForceClose  bool              // always write the closing tag, useful for XML
+	Dataset     map[string]string // written as data-* attributes
+	ARIA        map[string]string // written as aria-* attributes
+
+	// Allow ARIA keys that are not in ARIAAttributes, for attributes added
+	// to the specification since.
+	AllowUnknownARIA bool
+}
+
+func (n *Node) selfClosing() bool {
+	if n.SelfClosing {
+		return true
+	}
+	return !n.ForceClose && VoidElements[n.Tag]
+}
+
+// AddClass adds the given classes to the class attribute.
+func (n *Node) AddClass(classes ...string) *Node {
+	n.Attributes.Class(classes...)
+	return n
+}
+
+// RemoveClass removes a class from the class attribute.
+func (n *Node) RemoveClass(class string) *Node {
+	var remaining []string
+	for _, c := range strings.Fields(n.Attributes.Get("class")) {
+		if c != class {
+			remaining = append(remaining, c)
+		}
+	}
+	n.Attributes.Set("class", strings.Join(remaining, " "))
+	return n
+}
+
+// HasClass checks if the class attribute contains the given class.
+func (n *Node) HasClass(class string) bool {
+	for _, c := range strings.Fields(n.Attributes.Get("class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Clone returns a deep copy of the Node. Inner is cloned if it is a *Node,
+// other Inner values are shared with the copy.
+func (n *Node) Clone() *Node {
+	c := *n
+	if n.Attributes != nil {
+		c.Attributes = append(Attributes(nil), n.Attributes...)
+	}
+	c.Dataset = cloneStringMap(n.Dataset)
+	c.ARIA = cloneStringMap(n.ARIA)
+	if inner, ok := n.Inner.(*Node); ok && inner != nil {
+		c.Inner = inner.Clone()
+	}
+	return &c
+}
+
+func cloneStringMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
 }
 
 func (n *Node) HTML() (HTML, error) {
@@ -22,6 +111,33 @@ func (n *Node) Write(w io.Writer) (int, error) {
 	i := 0
 	var err error
 
+	i, err = n.writeOpen(w)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = Write(w, n.Inner)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = n.writeClose(w)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
+
+// Write the opening tag including the attributes.
+func (n *Node) writeOpen(w io.Writer) (int, error) {
+	written := 0
+	i := 0
+	var err error
+
 	i, err = fmt.Fprint(w, "<", n.Tag)
 	written += i
 	if err != nil {
@@ -34,25 +150,95 @@ func (n *Node) Write(w io.Writer) (int, error) {
 		return written, err
 	}
 
-	i, err = fmt.Fprint(w, ">")
+	for key := range n.Dataset {
+		if !validDataKey(key) {
+			return written, fmt.Errorf("Invalid data attribute key %q", key)
+		}
+	}
+	i, err = writeStringDict(w, "data-", n.Dataset)
 	written += i
 	if err != nil {
 		return written, err
 	}
 
-	i, err = Write(w, n.Inner)
+	if !n.AllowUnknownARIA {
+		for key := range n.ARIA {
+			if !ARIAAttributes[key] {
+				return written, fmt.Errorf("Unknown ARIA attribute %q", key)
+			}
+		}
+	}
+	i, err = writeStringDict(w, "aria-", n.ARIA)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = fmt.Fprint(w, ">")
 	written += i
 	if err != nil {
 		return written, err
 	}
 
-	if !n.SelfClosing {
-		i, err = fmt.Fprint(w, "</", n.Tag, ">")
+	return written, nil
+}
+
+// Write the closing tag, unless the Node is self closing.
+func (n *Node) writeClose(w io.Writer) (int, error) {
+	if n.selfClosing() {
+		return 0, nil
+	}
+	return fmt.Fprint(w, "</", n.Tag, ">")
+}
+
+// Render the Node as a string.
+func (n *Node) Render() (string, error) {
+	return Render(n)
+}
+
+// MustRender renders the Node as a string, and panics on error.
+func (n *Node) MustRender() string {
+	s, err := n.Render()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// WriteTo implements io.WriterTo by delegating to Write.
+func (n *Node) WriteTo(w io.Writer) (int64, error) {
+	i, err := n.Write(w)
+	return int64(i), err
+}
+
+// Check if a key is valid for a data-* attribute.
+func validDataKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Render a map of string attributes in sorted key order using the key prefix.
+func writeStringDict(w io.Writer, prefix string, dict map[string]string) (int, error) {
+	keys := make([]string, 0, len(dict))
+	for key := range dict {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var written, i int
+	var err error
+	for _, key := range keys {
+		i, err = writeKeyValue(w, prefix+key, dict[key])
 		written += i
 		if err != nil {
 			return written, err
 		}
 	}
-
 	return written, nil
 }
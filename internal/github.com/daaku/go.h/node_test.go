@@ -0,0 +1,163 @@
+package h_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+var _ io.WriterTo = &h.Node{}
+
+func TestNodeWriteTo(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	n, err := (&h.Node{Tag: "p", Inner: h.String("a")}).WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `<p>a</p>` || n != int64(buf.Len()) {
+		t.Fatalf("unexpected output %q with count %d", buf.String(), n)
+	}
+}
+
+func wideTree(n int) *h.Node {
+	f := make(h.Fragment, n)
+	for i := range f {
+		f[i] = &h.Node{Tag: "span", Inner: h.String("x")}
+	}
+	return &h.Node{Tag: "div", Inner: f}
+}
+
+func BenchmarkNodeWrite10000(b *testing.B) {
+	node := wideTree(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Write(io.Discard, node)
+	}
+}
+
+func BenchmarkNodeWriteTo10000(b *testing.B) {
+	node := wideTree(10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		node.WriteTo(io.Discard)
+	}
+}
+
+func TestNodeVoidElement(t *testing.T) {
+	t.Parallel()
+	assertRender(t, &h.Node{Tag: "br"}, `<br>`)
+}
+
+func TestNodeVoidElementForceClose(t *testing.T) {
+	t.Parallel()
+	assertRender(t, &h.Node{Tag: "br", ForceClose: true}, `<br></br>`)
+}
+
+func TestNodeClassManipulation(t *testing.T) {
+	t.Parallel()
+	n := &h.Node{Tag: "div"}
+	if n.HasClass("a") {
+		t.Fatal("did not expect class a")
+	}
+	n.AddClass("a", "b").AddClass("b", "c")
+	assertRender(t, n, `<div class="a b c"></div>`)
+	if !n.HasClass("b") {
+		t.Fatal("was expecting class b")
+	}
+	n.RemoveClass("b")
+	assertRender(t, n, `<div class="a c"></div>`)
+	if n.HasClass("b") {
+		t.Fatal("did not expect class b after removal")
+	}
+	n.RemoveClass("a").RemoveClass("c")
+	assertRender(t, n, `<div></div>`)
+}
+
+func TestNodeDataset(t *testing.T) {
+	t.Parallel()
+	n := &h.Node{
+		Tag:        "div",
+		Attributes: h.Attributes{{Key: "id", Value: "x"}},
+		Dataset:    map[string]string{"user-id": "42", "a1": `"q"`},
+	}
+	assertRender(t, n, `<div id="x" data-a1="&#34;q&#34;" data-user-id="42"></div>`)
+}
+
+func TestNodeDatasetInvalidKey(t *testing.T) {
+	t.Parallel()
+	for _, key := range []string{"", "userId", "a b", "a_b"} {
+		n := &h.Node{Tag: "div", Dataset: map[string]string{key: "x"}}
+		if _, err := h.Render(n); err == nil {
+			t.Fatalf("was expecting an error for key %q", key)
+		}
+	}
+}
+
+func TestNodeARIA(t *testing.T) {
+	t.Parallel()
+	n := &h.Node{
+		Tag:        "nav",
+		Attributes: h.Attributes{{Key: "role", Value: "navigation"}},
+		Dataset:    map[string]string{"x": "1"},
+		ARIA: map[string]string{
+			"label":       "Main",
+			"labelledby":  "title",
+			"describedby": "desc",
+			"hidden":      "true",
+		},
+	}
+	assertRender(t, n, `<nav role="navigation" data-x="1" aria-describedby="desc"`+
+		` aria-hidden="true" aria-label="Main" aria-labelledby="title"></nav>`)
+}
+
+func TestNodeARIAUnknown(t *testing.T) {
+	t.Parallel()
+	// role is a plain attribute, not aria-role.
+	n := &h.Node{Tag: "div", ARIA: map[string]string{"role": "button"}}
+	if _, err := h.Render(n); err == nil {
+		t.Fatal("was expecting an error for an unknown ARIA attribute")
+	}
+	n.AllowUnknownARIA = true
+	assertRender(t, n, `<div aria-role="button"></div>`)
+}
+
+func TestNodeClone(t *testing.T) {
+	t.Parallel()
+	base := &h.Node{
+		Tag:        "a",
+		Attributes: h.Attributes{{Key: "class", Value: "btn"}},
+		Dataset:    map[string]string{"id": "1"},
+		Inner:      &h.Node{Tag: "span", Inner: h.String("x")},
+	}
+	c := base.Clone().AddClass("primary")
+	c.Dataset["id"] = "2"
+	c.Inner.(*h.Node).Tag = "b"
+	assertRender(t, base, `<a class="btn" data-id="1"><span>x</span></a>`)
+	assertRender(t, c, `<a class="btn primary" data-id="2"><b>x</b></a>`)
+}
+
+func TestNodeRender(t *testing.T) {
+	t.Parallel()
+	n := &h.Node{Tag: "p", Inner: h.String("a")}
+	s, err := n.Render()
+	if err != nil || s != `<p>a</p>` {
+		t.Fatalf("unexpected output %q with error %v", s, err)
+	}
+	if s := n.MustRender(); s != `<p>a</p>` {
+		t.Fatalf("unexpected output %q", s)
+	}
+}
+
+func TestNodeMustRenderPanics(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if recover() == nil {
+			t.Fatal("was expecting a panic")
+		}
+	}()
+	(&h.Node{Tag: "p", Inner: h.Error(errors.New("fail"))}).MustRender()
+}
@@ -0,0 +1,76 @@
+package h
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+type nonceHolder struct {
+	once  sync.Once
+	nonce string
+}
+
+type contextNonceKeyT int
+
+var contextNonceKey = contextNonceKeyT(1)
+
+// WithNonce prepares the context to hold a CSP nonce, which is generated on
+// the first call to Nonce.
+func WithNonce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextNonceKey, &nonceHolder{})
+}
+
+var errNoNonce = errors.New("h: context was not created using WithNonce")
+
+// Nonce returns the random 128-bit base64 CSP nonce for the context. It
+// returns an empty string if the context was not created using WithNonce.
+func Nonce(ctx context.Context) string {
+	holder, ok := ctx.Value(contextNonceKey).(*nonceHolder)
+	if !ok {
+		return ""
+	}
+	holder.once.Do(func() {
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			panic(err)
+		}
+		holder.nonce = base64.StdEncoding.EncodeToString(b[:])
+	})
+	return holder.nonce
+}
+
+// Returns a node with the nonce from the context added to attrs, or an Error
+// if the context was not created using WithNonce.
+func nonceNode(ctx context.Context, tag string, attrs Attributes, inner HTML) HTML {
+	nonce := Nonce(ctx)
+	if nonce == "" {
+		return Error(errNoNonce)
+	}
+	attrs = append(attrs, Attr{Key: "nonce", Value: nonce})
+	return &Node{Tag: tag, Attributes: attrs, Inner: inner}
+}
+
+// ScriptWithNonce returns ExternalScript with the nonce from the context. The
+// context must be created using WithNonce, otherwise rendering fails.
+func ScriptWithNonce(ctx context.Context, src string) HTML {
+	return nonceNode(ctx, "script",
+		Attributes{{Key: "type", Value: "text/javascript"}, {Key: "src", Value: src}}, nil)
+}
+
+// InlineScriptWithNonce returns InlineScript with the nonce from the context.
+// The context must be created using WithNonce, otherwise rendering fails.
+func InlineScriptWithNonce(ctx context.Context, js string) HTML {
+	return nonceNode(ctx, "script",
+		Attributes{{Key: "type", Value: "text/javascript"}}, Unsafe(js))
+}
+
+// InlineStyleWithNonce returns InlineStyle with the nonce from the context.
+// The context must be created using WithNonce, otherwise rendering fails.
+func InlineStyleWithNonce(ctx context.Context, css string) HTML {
+	return nonceNode(ctx, "style",
+		Attributes{{Key: "type", Value: "text/css"}}, Unsafe(css))
+}
@@ -0,0 +1,55 @@
+package h_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+func TestNonceStable(t *testing.T) {
+	t.Parallel()
+	ctx := h.WithNonce(context.Background())
+	nonce := h.Nonce(ctx)
+	if nonce != h.Nonce(ctx) {
+		t.Fatal("was expecting the same nonce")
+	}
+	b, err := base64.StdEncoding.DecodeString(nonce)
+	if err != nil || len(b) != 16 {
+		t.Fatalf("invalid nonce %q", nonce)
+	}
+	if nonce == h.Nonce(h.WithNonce(context.Background())) {
+		t.Fatal("was expecting a different nonce for another context")
+	}
+}
+
+func TestNonceHelpers(t *testing.T) {
+	t.Parallel()
+	ctx := h.WithNonce(context.Background())
+	nonce := h.Nonce(ctx)
+	assertRender(t, h.ScriptWithNonce(ctx, "/a.js"), fmt.Sprintf(
+		`<script type="text/javascript" src="/a.js" nonce="%s"></script>`, nonce))
+	assertRender(t, h.InlineScriptWithNonce(ctx, "a()"), fmt.Sprintf(
+		`<script type="text/javascript" nonce="%s">a()</script>`, nonce))
+	assertRender(t, h.InlineStyleWithNonce(ctx, "a{}"), fmt.Sprintf(
+		`<style type="text/css" nonce="%s">a{}</style>`, nonce))
+}
+
+func TestNonceWithoutContext(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	if h.Nonce(ctx) != "" {
+		t.Fatal("was expecting an empty nonce")
+	}
+	for _, html := range []h.HTML{
+		h.ScriptWithNonce(ctx, "/a.js"),
+		h.InlineScriptWithNonce(ctx, "a()"),
+		h.InlineStyleWithNonce(ctx, "a{}"),
+	} {
+		if _, err := h.Render(html); err == nil {
+			t.Fatal("was expecting an error without WithNonce")
+		}
+	}
+}
@@ -23,41 +23,62 @@ func (n *ReflectNode) Write(w io.Writer) (int, error) {
 	i := 0
 	var err error
 
-	i, err = fmt.Fprint(w, "<", n.Tag)
+	i, err = n.writeOpen(w)
 	written += i
 	if err != nil {
 		return written, err
 	}
 
-	i, err = n.writeAttributes(w)
+	i, err = n.writeInner(w)
 	written += i
 	if err != nil {
 		return written, err
 	}
 
-	i, err = fmt.Fprint(w, ">")
+	i, err = n.writeClose(w)
 	written += i
 	if err != nil {
 		return written, err
 	}
 
-	i, err = n.writeInner(w)
+	return written, nil
+}
+
+// Write the opening tag including the attributes.
+func (n *ReflectNode) writeOpen(w io.Writer) (int, error) {
+	written := 0
+	i := 0
+	var err error
+
+	i, err = fmt.Fprint(w, "<", n.Tag)
 	written += i
 	if err != nil {
 		return written, err
 	}
 
-	if !n.SelfClosing {
-		i, err = fmt.Fprint(w, "</", n.Tag, ">")
-		written += i
-		if err != nil {
-			return written, err
-		}
+	i, err = n.writeAttributes(w)
+	written += i
+	if err != nil {
+		return written, err
+	}
+
+	i, err = fmt.Fprint(w, ">")
+	written += i
+	if err != nil {
+		return written, err
 	}
 
 	return written, nil
 }
 
+// Write the closing tag, unless the ReflectNode is self closing.
+func (n *ReflectNode) writeClose(w io.Writer) (int, error) {
+	if n.SelfClosing {
+		return 0, nil
+	}
+	return fmt.Fprint(w, "</", n.Tag, ">")
+}
+
 // Use reflection to write attributes.
 func (n *ReflectNode) writeAttributes(w io.Writer) (int, error) {
 	value := reflect.ValueOf(n.Node).Elem()
@@ -82,8 +103,7 @@ func (n *ReflectNode) writeAttributes(w io.Writer) (int, error) {
 				return written, fmt.Errorf(
 					"Invalid dict2 value: %+v of type %T", val, val)
 			}
-			attrs := Attributes(rawAttrs)
-			tmp, err = attrs.Write(w, strings.ToLower(field.Name)+"-")
+			tmp, err = writeDict(w, strings.ToLower(field.Name)+"-", rawAttrs)
 			written += tmp
 			if err != nil {
 				return written, err
@@ -93,13 +113,11 @@ func (n *ReflectNode) writeAttributes(w io.Writer) (int, error) {
 	return written, nil
 }
 
-// Use reflection to write inner HTML.
-func (n *ReflectNode) writeInner(w io.Writer) (int, error) {
+// Use reflection to find the inner HTML.
+func (n *ReflectNode) inner() ([]HTML, error) {
 	value := reflect.ValueOf(n.Node).Elem()
 	typeOf := value.Type()
-	written := 0
-	tmp := 0
-	var err error
+	var inner []HTML
 	for i := 0; i < typeOf.NumField(); i++ {
 		field := typeOf.Field(i)
 		if field.Tag.Get("h") != "inner" {
@@ -112,15 +130,20 @@ func (n *ReflectNode) writeInner(w io.Writer) (int, error) {
 		}
 		html, ok := fieldValue.(HTML)
 		if !ok {
-			return written, fmt.Errorf(
+			return inner, fmt.Errorf(
 				"Field %s was marked as inner but does not satisfy the HTML interface",
 				field.Name)
 		}
-		tmp, err = Write(w, html)
-		written += tmp
-		if err != nil {
-			return written, err
-		}
+		inner = append(inner, html)
 	}
-	return written, nil
+	return inner, nil
+}
+
+// Write the inner HTML.
+func (n *ReflectNode) writeInner(w io.Writer) (int, error) {
+	inner, err := n.inner()
+	if err != nil {
+		return 0, err
+	}
+	return Fragment(inner).Write(w)
 }
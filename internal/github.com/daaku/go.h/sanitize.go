@@ -0,0 +1,164 @@
+package h
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/html"
+)
+
+// SanitizePolicy configures Sanitize.
+type SanitizePolicy struct {
+	// Tags maps the allowed tag names to the attributes allowed on them.
+	Tags map[string][]string
+
+	// StripUnknown drops tags not in Tags, keeping their text. Otherwise the
+	// tags are escaped and appear as text. The contents of script and style
+	// elements are always dropped unless the tag is allowed.
+	StripUnknown bool
+}
+
+func (p *SanitizePolicy) allowedAttr(tag, key string) bool {
+	for _, a := range p.Tags[tag] {
+		if a == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Attributes containing a URL. Their values must pass safeURL.
+var urlAttrs = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"xlink:href": true,
+}
+
+// Schemes allowed in URL attributes. Relative URLs are also allowed.
+var safeSchemes = map[string]bool{
+	"http":   true,
+	"https":  true,
+	"mailto": true,
+}
+
+// Check if a URL is relative or uses a scheme in safeSchemes. Like browsers,
+// ASCII tab and newline are removed and leading and trailing C0 controls and
+// spaces are stripped before the scheme is considered.
+func safeURL(value string) bool {
+	v := strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, value)
+	v = strings.TrimFunc(v, func(r rune) bool { return r <= ' ' })
+	u, err := url.Parse(v)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "" || safeSchemes[u.Scheme]
+}
+
+// Check if every candidate URL in a srcset is safe.
+func safeSrcset(value string) bool {
+	for _, candidate := range strings.Split(value, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) > 0 && !safeURL(fields[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check if an attribute is unsafe regardless of the policy.
+func unsafeAttr(key, value string) bool {
+	switch {
+	case strings.HasPrefix(key, "on"):
+		return true
+	case key == "srcset":
+		return !safeSrcset(value)
+	case urlAttrs[key]:
+		return !safeURL(value)
+	}
+	return false
+}
+
+// Sanitize parses raw HTML and returns markup containing only the tags and
+// attributes allowed by the policy. Event handler attributes and URL attributes
+// that are not relative or http, https or mailto are always removed. Unclosed allowed tags are closed.
+func Sanitize(raw string, policy *SanitizePolicy) (HTML, error) {
+	if policy == nil {
+		policy = &SanitizePolicy{}
+	}
+	var buf bytes.Buffer
+	var open []string
+	skip := "" // script or style element whose content is being dropped
+	z := html.NewTokenizer(strings.NewReader(raw))
+	for {
+		if z.Next() == html.ErrorToken {
+			if err := z.Err(); err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		tok := z.Token()
+		_, allowed := policy.Tags[tok.Data]
+		if skip != "" {
+			if tok.Type == html.EndTagToken && tok.Data == skip {
+				skip = ""
+			}
+			continue
+		}
+		switch tok.Type {
+		case html.TextToken:
+			buf.WriteString(html.EscapeString(tok.Data))
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if !allowed {
+				if tok.Data == "script" || tok.Data == "style" {
+					if tok.Type == html.StartTagToken {
+						skip = tok.Data
+					}
+				} else if !policy.StripUnknown {
+					buf.WriteString(html.EscapeString(tok.String()))
+				}
+				continue
+			}
+			buf.WriteString("<" + tok.Data)
+			for _, a := range tok.Attr {
+				if a.Namespace != "" || !policy.allowedAttr(tok.Data, a.Key) ||
+					unsafeAttr(a.Key, a.Val) {
+					continue
+				}
+				buf.WriteString(" " + a.Key + `="` + EscapeAttr(a.Val) + `"`)
+			}
+			buf.WriteString(">")
+			if tok.Type == html.StartTagToken && !VoidElements[tok.Data] {
+				open = append(open, tok.Data)
+			}
+		case html.EndTagToken:
+			if !allowed {
+				if !policy.StripUnknown {
+					buf.WriteString(html.EscapeString(tok.String()))
+				}
+				continue
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.Data {
+					for j := len(open) - 1; j >= i; j-- {
+						buf.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		buf.WriteString("</" + open[i] + ">")
+	}
+	return UnsafeBytes(buf.Bytes()), nil
+}
@@ -0,0 +1,104 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+var testPolicy = &h.SanitizePolicy{
+	Tags: map[string][]string{
+		"p":  nil,
+		"b":  nil,
+		"a":  {"href", "title", "onclick"},
+		"br": nil,
+	},
+	StripUnknown: true,
+}
+
+func assertSanitize(t *testing.T, policy *h.SanitizePolicy, raw, expected string) {
+	html, err := h.Sanitize(raw, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRender(t, html, expected)
+}
+
+func TestSanitizeScript(t *testing.T) {
+	t.Parallel()
+	assertSanitize(t, testPolicy,
+		`<p>hi<script>alert("x")</script> there</p>`,
+		`<p>hi there</p>`)
+}
+
+func TestSanitizeEventAttributes(t *testing.T) {
+	t.Parallel()
+	assertSanitize(t, testPolicy,
+		`<a href="/x" onclick="evil()" style="color:red">x</a>`,
+		`<a href="/x">x</a>`)
+	assertSanitize(t, testPolicy,
+		`<a href=" JavaScript:evil()" title="t">x</a>`,
+		`<a title="t">x</a>`)
+}
+
+func TestSanitizeNested(t *testing.T) {
+	t.Parallel()
+	assertSanitize(t, testPolicy,
+		`<div><p>a <b>b<br/></b> <i>c</i> &amp; <a href="/">d</a></p></div>`,
+		`<p>a <b>b<br></b> c &amp; <a href="/">d</a></p>`)
+}
+
+func TestSanitizeUnbalanced(t *testing.T) {
+	t.Parallel()
+	assertSanitize(t, testPolicy, `<p><b>x</p></b>`, `<p><b>x</b></p>`)
+}
+
+func TestSanitizeEncodeUnknown(t *testing.T) {
+	t.Parallel()
+	policy := &h.SanitizePolicy{Tags: map[string][]string{"b": nil}}
+	assertSanitize(t, policy,
+		`<b>x</b><i class="y">z</i>`,
+		`<b>x</b>&lt;i class=&#34;y&#34;&gt;z&lt;/i&gt;`)
+}
+
+var urlPolicy = &h.SanitizePolicy{
+	Tags: map[string][]string{
+		"a":      {"href", "xlink:href"},
+		"iframe": {"src"},
+		"img":    {"src", "srcset"},
+		"form":   {"action"},
+		"button": {"formaction"},
+	},
+	StripUnknown: true,
+}
+
+func TestSanitizeUnsafeURLs(t *testing.T) {
+	t.Parallel()
+	cases := []struct{ raw, expected string }{
+		{"<a href=\"java\tscript:evil()\">x</a>", `<a>x</a>`},
+		{`<a href="java&#x0A;script:evil()">x</a>`, `<a>x</a>`},
+		{"<a href=\"\x01javascript:evil()\">x</a>", `<a>x</a>`},
+		{`<a href="vbscript:evil()">x</a>`, `<a>x</a>`},
+		{`<a xlink:href="javascript:evil()">x</a>`, `<a>x</a>`},
+		{`<iframe src="data:text/html,<script>evil()</script>"></iframe>`, `<iframe></iframe>`},
+		{`<img src="/a.png" srcset="/a.png 1x, javascript:evil() 2x">`, `<img src="/a.png">`},
+		{`<form action="javascript:evil()"></form>`, `<form></form>`},
+		{`<button formaction="javascript:evil()"></button>`, `<button></button>`},
+	}
+	for _, c := range cases {
+		assertSanitize(t, urlPolicy, c.raw, c.expected)
+	}
+}
+
+func TestSanitizeSafeURLs(t *testing.T) {
+	t.Parallel()
+	cases := []string{
+		`<a href="/relative?a=b">x</a>`,
+		`<a href="https://example.com/">x</a>`,
+		`<a href="mailto:a@example.com">x</a>`,
+		`<img srcset="/a.png 1x, https://example.com/b.png 2x">`,
+	}
+	for _, c := range cases {
+		assertSanitize(t, urlPolicy, c, c)
+	}
+}
@@ -9,3 +9,9 @@ type String string
 func (s String) HTML() (HTML, error) {
 	return Unsafe(html.EscapeString(string(s))), nil
 }
+
+// Safe returns HTML for the given string after escaping it. Use Unsafe for
+// trusted markup that should be written as is.
+func Safe(s string) HTML {
+	return Unsafe(html.EscapeString(s))
+}
@@ -0,0 +1,42 @@
+package h
+
+// The SVG namespace.
+const SVGNamespace = "http://www.w3.org/2000/svg"
+
+// SVGNode returns a Node for an SVG element. The namespace is set on svg
+// elements if it isn't already present.
+func SVGNode(tag string, attrs Attributes, inner HTML) HTML {
+	if tag == "svg" && attrs.Get("xmlns") == "" {
+		attrs = append(Attributes{{Key: "xmlns", Value: SVGNamespace}}, attrs...)
+	}
+	return &Node{Tag: tag, Attributes: attrs, Inner: inner}
+}
+
+// SVG returns a root svg element.
+func SVG(attrs Attributes, inner HTML) HTML {
+	return SVGNode("svg", attrs, inner)
+}
+
+func Circle(attrs Attributes) HTML {
+	return SVGNode("circle", attrs, nil)
+}
+
+func Rect(attrs Attributes) HTML {
+	return SVGNode("rect", attrs, nil)
+}
+
+func Path(attrs Attributes) HTML {
+	return SVGNode("path", attrs, nil)
+}
+
+func Use(attrs Attributes) HTML {
+	return SVGNode("use", attrs, nil)
+}
+
+func G(attrs Attributes, inner HTML) HTML {
+	return SVGNode("g", attrs, inner)
+}
+
+func Defs(inner HTML) HTML {
+	return SVGNode("defs", nil, inner)
+}
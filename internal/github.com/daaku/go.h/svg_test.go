@@ -0,0 +1,28 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestSVG(t *testing.T) {
+	t.Parallel()
+	svg := h.SVG(h.Attrs(h.Attr{Key: "width", Value: "10"}), h.Fragment{
+		h.Defs(h.Rect(h.Attrs(h.ID("r")))),
+		h.G(nil, h.Circle(h.Attrs(h.Attr{Key: "r", Value: "5"}))),
+		h.Use(h.Attrs(h.Href("#r"))),
+		h.Path(h.Attrs(h.Attr{Key: "d", Value: "M0 0"})),
+	})
+	assertRender(t, svg, `<svg xmlns="http://www.w3.org/2000/svg" width="10">`+
+		`<defs><rect id="r"></rect></defs>`+
+		`<g><circle r="5"></circle></g>`+
+		`<use href="#r"></use>`+
+		`<path d="M0 0"></path></svg>`)
+}
+
+func TestSVGNodeExistingNamespace(t *testing.T) {
+	t.Parallel()
+	svg := h.SVGNode("svg", h.Attrs(h.Attr{Key: "xmlns", Value: "x"}), nil)
+	assertRender(t, svg, `<svg xmlns="x"></svg>`)
+}
@@ -7,10 +7,14 @@ import (
 
 type UnsafeBytes []byte
 
+// RawBytes is an alias for UnsafeBytes.
+type RawBytes = UnsafeBytes
+
 func (u UnsafeBytes) HTML() (HTML, error) {
 	return u, fmt.Errorf("UnsafeBytes.HTML called for %s", u)
 }
 
+// Write the bytes as is, without copying.
 func (u UnsafeBytes) Write(w io.Writer) (int, error) {
-	return fmt.Fprintf(w, "%s", u)
+	return w.Write(u)
 }
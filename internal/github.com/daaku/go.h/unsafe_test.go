@@ -0,0 +1,26 @@
+package h_test
+
+import (
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+)
+
+func TestUnsafe(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.Unsafe(`<b>"hi"</b>`), `<b>"hi"</b>`)
+}
+
+func TestSafe(t *testing.T) {
+	t.Parallel()
+	assertRender(t, h.Safe(`<b>"hi"</b>`), `&lt;b&gt;&#34;hi&#34;&lt;/b&gt;`)
+}
+
+func TestRawBytes(t *testing.T) {
+	t.Parallel()
+	assertRender(t, &h.Node{
+		Tag:        "script",
+		Attributes: h.Attrs(h.Type("application/json")),
+		Inner:      h.RawBytes(`{"a":"<b>"}`),
+	}, `<script type="application/json">{"a":"<b>"}</script>`)
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"go/build"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/daaku/rell/adminweb"
@@ -27,6 +29,7 @@ import (
 	"github.com/daaku/rell/internal/github.com/facebookgo/httpdown"
 	"github.com/daaku/rell/internal/github.com/facebookgo/parse"
 	"github.com/daaku/rell/internal/github.com/golang/groupcache/lru"
+	"github.com/daaku/rell/middleware"
 	"github.com/daaku/rell/oauth"
 	"github.com/daaku/rell/og"
 	"github.com/daaku/rell/og/viewog"
@@ -52,6 +55,13 @@ func pkgDir(path string) string {
 	return pkg.Dir
 }
 
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
 func main() {
 	const signedRequestMaxAge = time.Hour * 24
 	runtime.GOMAXPROCS(runtime.NumCPU())
@@ -71,6 +81,18 @@ func main() {
 		"public-dir", pkgDir("github.com/daaku/rell/public"), "public files directory")
 	examplesDir := flagSet.String(
 		"examples-dir", pkgDir("github.com/daaku/rell/examples/db"), "example files directory")
+	infoCORSOrigins := flagSet.String(
+		"info-cors-origins", "", "comma separated origins allowed to fetch /info/")
+	metricsToken := flagSet.String("metrics-token", "", "bearer token for /metrics")
+	infoRateLimit := flagSet.Float64(
+		"info-rate-limit", 0, "per ip requests per second allowed to /info/, 0 to disable")
+	infoRateBurst := flagSet.Int("info-rate-burst", 10, "per ip burst allowed to /info/")
+	requestTimeout := flagSet.Duration("request-timeout", 0, "timeout for handling a request, 0 to disable")
+	accessLog := flagSet.Bool("access-log", false, "log every request")
+	readyRequiresRevision := flagSet.Bool(
+		"ready-requires-revision", false, "fail /healthz/ready if the build revision is unknown")
+	debug := flagSet.Bool("debug", false, "enable the /debug/ endpoint")
+	debugToken := flagSet.String("debug-token", "", "token required by /debug/")
 
 	flagSet.Parse(os.Args[1:])
 	if err := flagenv.ParseSet("RELL_", flagSet); err != nil {
@@ -78,9 +100,15 @@ func main() {
 		os.Exit(2)
 	}
 
+	if *debug && *debugToken == "" {
+		fmt.Fprintln(os.Stderr, "-debug requires -debug-token")
+		os.Exit(2)
+	}
+
 	if *dev {
 		devrestarter.Init()
 	}
+	viewcontext.RequireRevision(!*dev)
 
 	logger := log.New(os.Stderr, "", log.LstdFlags)
 	// for systemd started servers we can skip the date/time since journald
@@ -150,6 +178,10 @@ func main() {
 		DB:    examples.MustMakeDB(*examplesDir),
 		Cache: lruCache,
 	}
+	var accessLogger *slog.Logger
+	if *accessLog {
+		accessLogger = slog.New(slog.NewTextHandler(os.Stderr, nil))
+	}
 	webHandler := &web.Handler{
 		Static: static,
 		App:    fbApp,
@@ -161,8 +193,19 @@ func main() {
 			SignedRequestMaxAge: signedRequestMaxAge,
 			Forwarded:           forwarded,
 		},
-		PublicFS:       publicFS,
-		ContextHandler: &viewcontext.Handler{},
+		PublicFS: publicFS,
+		ContextHandler: &viewcontext.Handler{
+			App:                   fbApp,
+			ReadyRequiresRevision: *readyRequiresRevision,
+			CORSOrigins:           splitList(*infoCORSOrigins),
+			Counters:              &viewcontext.Counters{},
+			MetricsToken:          *metricsToken,
+			DebugEnabled:          *debug,
+			DebugToken:            *debugToken,
+			DebugContextKeys: map[string]interface{}{
+				"requestID": middleware.RequestIDKey,
+			},
+		},
 		ExamplesHandler: &viewexamples.Handler{
 			ExampleStore: exampleStore,
 			Xsrf:         xsrf,
@@ -178,6 +221,13 @@ func main() {
 			HttpTransport: httpTransport,
 			Static:        static,
 		},
+		InfoRateLimit:  *infoRateLimit,
+		InfoRateBurst:  *infoRateBurst,
+		RequestTimeout: *requestTimeout,
+		AccessLog:      accessLogger,
+		SecurityHeaders: viewcontext.SecurityHeadersOptions{
+			XContentTypeOptions: "nosniff",
+		},
 		AdminHandler: &adminweb.Handler{
 			Forwarded: forwarded,
 			Path:      *adminPath,
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/middleware"
+)
+
+var uuidRegexp = regexp.MustCompile(
+	`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func serveRequestID(r *http.Request) (string, *httptest.ResponseRecorder) {
+	var seen string
+	handler := middleware.RequestIDMiddleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = middleware.RequestIDFromContext(r.Context())
+		}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return seen, w
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	t.Parallel()
+	seen, w := serveRequestID(httptest.NewRequest("GET", "/", nil))
+	ensure.True(t, uuidRegexp.MatchString(seen), seen)
+	ensure.DeepEqual(t, w.Header().Get(middleware.RequestIDHeader), seen)
+}
+
+func TestRequestIDFromHeader(t *testing.T) {
+	t.Parallel()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(middleware.RequestIDHeader, "abc")
+	seen, w := serveRequestID(r)
+	ensure.DeepEqual(t, seen, "abc")
+	ensure.DeepEqual(t, w.Header().Get(middleware.RequestIDHeader), "abc")
+}
@@ -0,0 +1,35 @@
+package rellenv
+
+import (
+	"errors"
+
+	"github.com/daaku/rell/internal/github.com/daaku/ctxerr"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+// Bundle groups request scoped values so they can be added to the context
+// together. The Env in a Bundle is also returned by FromContext.
+type Bundle struct {
+	Env       *Env
+	UserID    string
+	SessionID string
+}
+
+type contextBundleKeyT int
+
+var contextBundleKey = contextBundleKeyT(1)
+
+var errBundleNotFound = errors.New("rellenv: Bundle not found in Context")
+
+// WithBundle adds the given bundle to the context.
+func WithBundle(ctx context.Context, b *Bundle) context.Context {
+	return context.WithValue(ctx, contextBundleKey, b)
+}
+
+// BundleFromContext retrieves the Bundle from the Context.
+func BundleFromContext(ctx context.Context) (*Bundle, error) {
+	if b, ok := ctx.Value(contextBundleKey).(*Bundle); ok {
+		return b, nil
+	}
+	return nil, ctxerr.Wrap(ctx, errBundleNotFound)
+}
@@ -0,0 +1,32 @@
+package rellenv_test
+
+import (
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/rellenv"
+)
+
+func TestBundleFromContext(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	b := &rellenv.Bundle{Env: env, UserID: "1", SessionID: "s"}
+	ctx := rellenv.WithBundle(context.Background(), b)
+
+	actual, err := rellenv.BundleFromContext(ctx)
+	ensure.Nil(t, err)
+	ensure.True(t, actual == b)
+
+	fromCtx, err := rellenv.FromContext(ctx)
+	ensure.Nil(t, err)
+	ensure.True(t, fromCtx == env)
+}
+
+func TestBundleNotFound(t *testing.T) {
+	t.Parallel()
+	_, err := rellenv.BundleFromContext(context.Background())
+	ensure.Err(t, err, regexp.MustCompile(`Bundle not found`))
+}
@@ -12,6 +12,7 @@ import (
 	"path"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/daaku/rell/internal/github.com/daaku/ctxerr"
@@ -23,7 +24,10 @@ import (
 	"github.com/daaku/rell/internal/golang.org/x/net/context"
 )
 
-var envRegexp = regexp.MustCompile(`^[a-zA-Z0-9-_.]*$`)
+var (
+	envRegexp    = regexp.MustCompile(`^[a-zA-Z0-9-_.]*$`)
+	localeRegexp = regexp.MustCompile(`^[a-z]{2}_[A-Z]{2}$`)
+)
 
 const (
 	// View Modes.
@@ -102,16 +106,17 @@ func (p *Parser) FromRequest(ctx context.Context, r *http.Request) (*Env, error)
 	if level := r.FormValue("level"); level != "" {
 		e.level = level
 	}
-	if locale := r.FormValue("locale"); locale != "" {
+	if locale := r.FormValue("locale"); localeRegexp.MatchString(locale) {
 		e.locale = locale
 	}
 	if env := r.FormValue("server"); env != "" {
 		e.Env = env
 	}
-	if viewMode := r.FormValue("view-mode"); viewMode != "" {
+	switch viewMode := r.FormValue("view-mode"); viewMode {
+	case Website, Canvas, PageTab:
 		e.ViewMode = viewMode
 	}
-	if module := r.FormValue("module"); module != "" {
+	if module := r.FormValue("module"); ValidateSDKVersion(module) == nil {
 		e.Module = module
 	}
 	if status, err := strconv.ParseBool(r.FormValue("status")); err == nil {
@@ -126,18 +131,17 @@ func (p *Parser) FromRequest(ctx context.Context, r *http.Request) (*Env, error)
 
 	var err error
 	rawSr := r.FormValue("signed_request")
-	if rawSr != "" {
+	if sr, ok := SignedRequestFromContext(r.Context()); ok {
+		e.SignedRequest = sr
+		e.ViewMode = signedRequestViewMode(sr)
+	} else if rawSr != "" {
 		e.SignedRequest, err = fbsr.Unmarshal(
 			[]byte(rawSr),
 			p.App.SecretByte(),
 			p.SignedRequestMaxAge,
 		)
 		if err == nil {
-			if e.SignedRequest.Page != nil {
-				e.ViewMode = PageTab
-			} else {
-				e.ViewMode = Canvas
-			}
+			e.ViewMode = signedRequestViewMode(e.SignedRequest)
 		}
 	} else {
 		cookie, _ := r.Cookie(fmt.Sprintf("fbsr_%d", e.appID))
@@ -161,23 +165,106 @@ func (p *Parser) FromRequest(ctx context.Context, r *http.Request) (*Env, error)
 	return e, nil
 }
 
+// Option configures the Parser used by FromRequest.
+type Option func(*Parser)
+
+// WithApp sets the default application.
+func WithApp(app fbapp.App) Option {
+	return func(p *Parser) { p.App = app }
+}
+
+// WithForwarded configures trust of forwarding headers.
+func WithForwarded(f *trustforward.Forwarded) Option {
+	return func(p *Parser) { p.Forwarded = f }
+}
+
+// WithEmpChecker sets the EmpChecker.
+func WithEmpChecker(c EmpChecker) Option {
+	return func(p *Parser) { p.EmpChecker = c }
+}
+
+// WithAppNSFetcher sets the AppNSFetcher.
+func WithAppNSFetcher(f AppNSFetcher) Option {
+	return func(p *Parser) { p.AppNSFetcher = f }
+}
+
+// WithSignedRequestMaxAge sets the maximum age of signed requests.
+func WithSignedRequestMaxAge(d time.Duration) Option {
+	return func(p *Parser) { p.SignedRequestMaxAge = d }
+}
+
+type noopEmpChecker struct{}
+
+func (noopEmpChecker) Check(uint64) bool { return false }
+
+type noopAppNSFetcher struct{}
+
+func (noopAppNSFetcher) Get(uint64) string { return "" }
+
+// FromRequest creates an Env from the URL parameters and cookies in the
+// request, for handlers that do not have one in their context. Without
+// options, the default application is used, forwarding headers are not
+// trusted and no employee or namespace lookups are made.
+func FromRequest(r *http.Request, opts ...Option) (*Env, error) {
+	p := &Parser{
+		App:          defaultFbApp,
+		Forwarded:    &trustforward.Forwarded{},
+		EmpChecker:   noopEmpChecker{},
+		AppNSFetcher: noopAppNSFetcher{},
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return p.FromRequest(context.Background(), r)
+}
+
 // Provides a duplicate copy.
 func (c *Env) Copy() *Env {
 	context := *c
 	return &context
 }
 
-// Get the URL for the JS SDK.
+// Clone provides a copy that shares no mutable state with the original,
+// including the SignedRequest.
+func (c *Env) Clone() *Env {
+	e := c.Copy()
+	if c.SignedRequest != nil {
+		sr := *c.SignedRequest
+		if sr.User != nil {
+			user := *sr.User
+			if user.Age != nil {
+				age := *user.Age
+				user.Age = &age
+			}
+			sr.User = &user
+		}
+		if sr.Page != nil {
+			page := *sr.Page
+			sr.Page = &page
+		}
+		e.SignedRequest = &sr
+	}
+	return e
+}
+
+// WithLocale returns a clone with the given locale.
+func (c *Env) WithLocale(locale string) *Env {
+	e := c.Clone()
+	e.locale = locale
+	return e
+}
+
+// Get the URL for the JS SDK. Unknown SDK versions use the default.
 func (c *Env) SdkURL() string {
 	server := "connect.facebook.net"
 	if c.Env != "" {
 		server = fburl.Hostname("static", c.Env) + "/assets.php"
 	}
-	return fmt.Sprintf("%s://%s/%s/%s.js", c.Scheme, server, c.locale, c.Module)
+	return fmt.Sprintf("%s://%s/%s/%s.js", c.Scheme, server, c.locale, c.sdkVersion())
 }
 
 // Get the URL for loading this application in a Page Tab on Facebook.
-func (c *Env) PageTabURL(name string) string {
+func (c *Env) PageTabURL(name string) (*url.URL, error) {
 	values := url.Values{}
 	values.Set("sk", fmt.Sprintf("app_%d", c.appID))
 	values.Set("app_data", appdata.Encode(c.URL(name)))
@@ -188,11 +275,20 @@ func (c *Env) PageTabURL(name string) string {
 		Path:      "/pages/Rell-Page-for-Tabs/141929622497380",
 		Values:    values,
 	}
-	return url.String()
+	return url.URL(), nil
+}
+
+// PageTabURLString is like PageTabURL but returns a string.
+func (c *Env) PageTabURLString(name string) string {
+	u, err := c.PageTabURL(name)
+	if err != nil {
+		return ""
+	}
+	return u.String()
 }
 
 // Get the URL for loading this application in a Canvas page on Facebook.
-func (c *Env) CanvasURL(name string) string {
+func (c *Env) CanvasURL(name string) (*url.URL, error) {
 	var base = "/" + c.appNamespace + "/"
 	if name == "" || name == "/" {
 		name = base
@@ -207,7 +303,16 @@ func (c *Env) CanvasURL(name string) string {
 		Path:      name,
 		Values:    c.Values(),
 	}
-	return url.String()
+	return url.URL(), nil
+}
+
+// CanvasURLString is like CanvasURL but returns a string.
+func (c *Env) CanvasURLString(name string) string {
+	u, err := c.CanvasURL(name)
+	if err != nil {
+		return ""
+	}
+	return u.String()
 }
 
 // Serialize the context back to URL values.
@@ -253,19 +358,33 @@ func (c *Env) AbsoluteURL(path string) *url.URL {
 	return u
 }
 
+// OAuthRedirectURL returns the absolute URL for path on the app's canvas URL
+// base, as configured in the app settings, for use as an OAuth redirect URI.
+// It does not include the context values since the redirect URI must match
+// exactly.
+func (c *Env) OAuthRedirectURL(path string) (*url.URL, error) {
+	if c.Scheme == "" || c.Host == "" {
+		return nil, fmt.Errorf("rellenv: missing scheme or host for redirect URL")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return &url.URL{Scheme: c.Scheme, Host: c.Host, Path: path}, nil
+}
+
 // This will return a view aware URL and will always be absolute.
 func (c *Env) ViewURL(path string) string {
 	switch c.ViewMode {
 	case Canvas:
-		return c.CanvasURL(path)
+		return c.CanvasURLString(path)
 	case PageTab:
-		return c.PageTabURL(path)
+		return c.PageTabURLString(path)
 	default:
 		return c.AbsoluteURL(path).String()
 	}
 }
 
-// JSON representation of Context.
+// JSON representation of Context, including the computed URLs.
 func (c *Env) MarshalJSON() ([]byte, error) {
 	data := map[string]interface{}{
 		"appID":                strconv.FormatUint(c.appID, 10),
@@ -275,6 +394,9 @@ func (c *Env) MarshalJSON() ([]byte, error) {
 		"signedRequest":        c.SignedRequest,
 		"viewMode":             c.ViewMode,
 		"init":                 c.Init,
+		"pageTabURL":           c.PageTabURLString("/"),
+		"canvasURL":            c.CanvasURLString("/"),
+		"sdkURL":               c.SdkURL(),
 	}
 	if c.isEmployee {
 		data["isEmployee"] = true
@@ -282,6 +404,48 @@ func (c *Env) MarshalJSON() ([]byte, error) {
 	return json.Marshal(data)
 }
 
+// PublicEnv is the subset of the Env that is safe to expose. It excludes the
+// signed request which contains tokens.
+type PublicEnv struct {
+	AppID      string `json:"appID"`
+	Locale     string `json:"locale"`
+	SDKVersion string `json:"sdkVersion"`
+	CanvasURL  string `json:"canvasURL"`
+	PageTabURL string `json:"pageTabURL"`
+}
+
+// Public returns the public fields of the Env.
+func (c *Env) Public() PublicEnv {
+	return PublicEnv{
+		AppID:      strconv.FormatUint(c.appID, 10),
+		Locale:     c.locale,
+		SDKVersion: c.sdkVersion(),
+		CanvasURL:  c.CanvasURLString("/"),
+		PageTabURL: c.PageTabURLString("/"),
+	}
+}
+
+// Validate returns an error describing every invalid field, or nil if the Env
+// is valid.
+func (c *Env) Validate() error {
+	var errs []error
+	if c.appID == 0 {
+		errs = append(errs, errors.New("rellenv: missing app ID"))
+	}
+	if !localeRegexp.MatchString(c.locale) {
+		errs = append(errs, fmt.Errorf("rellenv: invalid locale %q", c.locale))
+	}
+	if err := ValidateSDKVersion(c.Module); err != nil {
+		errs = append(errs, err)
+	}
+	switch c.ViewMode {
+	case Website, Canvas, PageTab:
+	default:
+		errs = append(errs, fmt.Errorf("rellenv: invalid view mode %q", c.ViewMode))
+	}
+	return errors.Join(errs...)
+}
+
 type contextEnvKeyT int
 
 var contextEnvKey = contextEnvKeyT(1)
@@ -289,14 +453,28 @@ var contextEnvKey = contextEnvKeyT(1)
 var errEnvNotFound = errors.New("rellenv: Env not found in Context")
 
 // FromContext retrieves the Env from the Context. If one isn't found, an error
-// is returned.
+// is returned. Invalid URL values are dropped by FromRequest, so the Env is not
+// validated here.
 func FromContext(ctx context.Context) (*Env, error) {
-	if e, ok := ctx.Value(contextEnvKey).(*Env); ok {
+	if e, ok := EnvFromContext(ctx); ok {
 		return e, nil
 	}
 	return nil, ctxerr.Wrap(ctx, errEnvNotFound)
 }
 
+// EnvFromContext retrieves the Env from the Context, or from a Bundle in the
+// Context, reporting whether one was found. This is useful for components rendered
+// using h.WriteContext.
+func EnvFromContext(ctx context.Context) (*Env, bool) {
+	if e, ok := ctx.Value(contextEnvKey).(*Env); ok {
+		return e, true
+	}
+	if b, ok := ctx.Value(contextBundleKey).(*Bundle); ok && b.Env != nil {
+		return b.Env, true
+	}
+	return nil, false
+}
+
 // WithEnv adds the given env to the context.
 func WithEnv(ctx context.Context, env *Env) context.Context {
 	return context.WithValue(ctx, contextEnvKey, env)
@@ -1,11 +1,16 @@
 package rellenv_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"testing"
 
+	"github.com/daaku/rell/internal/github.com/daaku/go.h"
+	"github.com/daaku/rell/internal/github.com/daaku/go.signedrequest/fbsr"
 	"github.com/daaku/rell/internal/github.com/daaku/go.trustforward"
 	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
 	"github.com/daaku/rell/internal/github.com/facebookgo/fbapp"
@@ -96,7 +101,7 @@ func TestComplex(t *testing.T) {
 func TestPageTabURLBeta(t *testing.T) {
 	t.Parallel()
 	env, _ := fromValues(t, url.Values{"server": []string{"beta"}})
-	pageTabURL := env.PageTabURL("/")
+	pageTabURL := env.PageTabURLString("/")
 	ensure.StringContains(t, pageTabURL,
 		"http://www.beta.facebook.com/pages/Rell-Page-for-Tabs/141929622497380")
 }
@@ -104,7 +109,7 @@ func TestPageTabURLBeta(t *testing.T) {
 func TestPageTabURL(t *testing.T) {
 	t.Parallel()
 	env, _ := fromValues(t, url.Values{})
-	pageTabURL := env.PageTabURL("/")
+	pageTabURL := env.PageTabURLString("/")
 	ensure.StringContains(t, pageTabURL,
 		"http://www.facebook.com/pages/Rell-Page-for-Tabs/141929622497380")
 	ensure.StringContains(t, pageTabURL, fmt.Sprintf("app_%d", defaultFacebookAppID))
@@ -114,7 +119,7 @@ func TestPageTabURL(t *testing.T) {
 func TestCanvasURLBeta(t *testing.T) {
 	t.Parallel()
 	env, _ := fromValues(t, url.Values{"server": []string{"beta"}})
-	canvasURL := env.CanvasURL("/")
+	canvasURL := env.CanvasURLString("/")
 	ensure.StringContains(t, canvasURL,
 		fmt.Sprintf("https://apps.beta.facebook.com/%s/?server=beta", defaultAppNS))
 }
@@ -122,7 +127,147 @@ func TestCanvasURLBeta(t *testing.T) {
 func TestCanvasURL(t *testing.T) {
 	t.Parallel()
 	env, _ := fromValues(t, url.Values{})
-	canvasURL := env.CanvasURL("/")
+	canvasURL := env.CanvasURLString("/")
 	ensure.StringContains(t, canvasURL,
 		fmt.Sprintf("https://apps.facebook.com/%s/", defaultAppNS))
 }
+
+func TestFromRequest(t *testing.T) {
+	t.Parallel()
+	req, err := http.NewRequest("GET", "http://www.fbrell.com/?locale=en_PI&appid=123", nil)
+	ensure.Nil(t, err)
+	env, err := rellenv.FromRequest(req,
+		rellenv.WithApp(fbapp.New(defaultFacebookAppID, "", "")),
+		rellenv.WithAppNSFetcher(funcAppNSFetcher(func(uint64) string { return defaultAppNS })),
+	)
+	ensure.Nil(t, err)
+	ensure.StringContains(t, env.SdkURL(), "en_PI")
+	ensure.DeepEqual(t, rellenv.FbApp(rellenv.WithEnv(context.Background(), env)).ID(), uint64(123))
+	ensure.StringContains(t, env.CanvasURLString("/"), defaultAppNS)
+}
+
+func TestFromRequestDefaults(t *testing.T) {
+	t.Parallel()
+	req, err := http.NewRequest("GET", "http://www.fbrell.com/", nil)
+	ensure.Nil(t, err)
+	env, err := rellenv.FromRequest(req)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, env.Host, "www.fbrell.com")
+}
+
+func TestClone(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	env.SignedRequest = &fbsr.SignedRequest{UserID: 1}
+	clone := env.Clone()
+	clone.SignedRequest.UserID = 2
+	clone.Status = false
+	ensure.DeepEqual(t, env.SignedRequest.UserID, uint64(1))
+	ensure.True(t, env.Status)
+}
+
+func TestWithLocale(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	other := env.WithLocale("en_PI")
+	ensure.StringContains(t, other.SdkURL(), "en_PI")
+	ensure.StringContains(t, env.SdkURL(), "en_US")
+}
+
+func TestMarshalJSONURLs(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	b, err := json.Marshal(env)
+	ensure.Nil(t, err)
+	var data map[string]interface{}
+	ensure.Nil(t, json.Unmarshal(b, &data))
+	ensure.DeepEqual(t, data["pageTabURL"], env.PageTabURLString("/"))
+	ensure.DeepEqual(t, data["canvasURL"], env.CanvasURLString("/"))
+	ensure.DeepEqual(t, data["sdkURL"], env.SdkURL())
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	ensure.Nil(t, env.Validate())
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	t.Parallel()
+	env := (&rellenv.Parser{App: fbapp.New(0, "", "")}).Default().WithLocale("bogus")
+	env.ViewMode = "nope"
+	err := env.Validate()
+	ensure.Err(t, err, regexp.MustCompile(`missing app ID`))
+	ensure.Err(t, err, regexp.MustCompile(`invalid locale "bogus"`))
+	ensure.Err(t, err, regexp.MustCompile(`invalid view mode "nope"`))
+}
+
+func TestFromContextInvalidValuesUseDefaults(t *testing.T) {
+	t.Parallel()
+	_, ctx := fromValues(t, url.Values{
+		"locale":    []string{"en"},
+		"view-mode": []string{"bogus"},
+	})
+	env, err := rellenv.FromContext(ctx)
+	ensure.Nil(t, err)
+	ensure.Nil(t, env.Validate())
+	ensure.DeepEqual(t, env.Public().Locale, "en_US")
+	ensure.DeepEqual(t, env.ViewMode, rellenv.Website)
+}
+
+func TestCanvasURLValues(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	u, err := env.CanvasURL("/foo")
+	ensure.Nil(t, err)
+	q := u.Query()
+	q.Set("a", "b&c")
+	u.RawQuery = q.Encode()
+	ensure.DeepEqual(t, u.String(),
+		fmt.Sprintf("https://apps.facebook.com/%s/foo?a=b%%26c", defaultAppNS))
+}
+
+func TestEnvFromContext(t *testing.T) {
+	t.Parallel()
+	_, ok := rellenv.EnvFromContext(context.Background())
+	ensure.False(t, ok)
+
+	env, ctx := fromValues(t, url.Values{"locale": []string{"bogus"}})
+	actual, ok := rellenv.EnvFromContext(ctx)
+	ensure.True(t, ok)
+	ensure.True(t, actual == env)
+}
+
+func TestEnvFromContextRendering(t *testing.T) {
+	t.Parallel()
+	env, ctx := fromValues(t, url.Values{})
+	component := h.Contextual(func(ctx context.Context) h.HTML {
+		env, _ := rellenv.EnvFromContext(ctx)
+		return &h.A{HREF: env.SdkURL()}
+	})
+	var buf bytes.Buffer
+	_, err := h.WriteContext(ctx, &buf, h.Contextual(func(context.Context) h.HTML {
+		return &h.Div{Inner: &h.P{Inner: component}}
+	}))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, buf.String(),
+		fmt.Sprintf(`<div><p><a href="%s"></a></p></div>`, env.SdkURL()))
+}
+
+func TestOAuthRedirectURL(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{"locale": []string{"fr_FR"}})
+	u, err := env.OAuthRedirectURL("/auth/session")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, u.String(), "http://www.fbrell.com/auth/session")
+
+	env.Scheme = "https"
+	u, err = env.OAuthRedirectURL("auth/session")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, u.String(), "https://www.fbrell.com/auth/session")
+	ensure.True(t, u.IsAbs())
+
+	env.Host = ""
+	_, err = env.OAuthRedirectURL("/")
+	ensure.Err(t, err, regexp.MustCompile(`missing scheme or host`))
+}
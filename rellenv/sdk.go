@@ -0,0 +1,39 @@
+package rellenv
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// KnownSDKVersions are the JS SDK modules that SdkURL will load. Unknown
+// versions fall back to the default module.
+var KnownSDKVersions = []string{"all", "all/debug", "sdk", "sdk/debug"}
+
+var errEmptySDKVersion = errors.New("rellenv: empty SDK version")
+
+// ValidateSDKVersion checks the version against KnownSDKVersions.
+func ValidateSDKVersion(version string) error {
+	if version == "" {
+		return errEmptySDKVersion
+	}
+	for _, v := range KnownSDKVersions {
+		if v == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("rellenv: unknown SDK version %q", version)
+}
+
+// Returns the SDK module to load, falling back to the default with a warning
+// if the module is unknown. FromRequest already drops unknown modules from the
+// URL, so the warning is only logged for a Module set directly in code and
+// cannot be triggered by clients.
+func (c *Env) sdkVersion() string {
+	if err := ValidateSDKVersion(c.Module); err != nil {
+		slog.Warn("rellenv: using the default SDK version",
+			"error", err, "default", defaultContext.Module)
+		return defaultContext.Module
+	}
+	return c.Module
+}
@@ -0,0 +1,63 @@
+package rellenv_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"net/url"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/rellenv"
+)
+
+func TestValidateSDKVersion(t *testing.T) {
+	t.Parallel()
+	ensure.Nil(t, rellenv.ValidateSDKVersion("all"))
+	ensure.Nil(t, rellenv.ValidateSDKVersion("sdk/debug"))
+	ensure.Err(t, rellenv.ValidateSDKVersion("evil"),
+		regexp.MustCompile(`unknown SDK version "evil"`))
+	ensure.Err(t, rellenv.ValidateSDKVersion(""),
+		regexp.MustCompile(`empty SDK version`))
+}
+
+func TestSdkURLKnownVersion(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{"module": []string{"sdk/debug"}})
+	ensure.DeepEqual(t, env.SdkURL(), "http://connect.facebook.net/en_US/sdk/debug.js")
+}
+
+func TestSdkURLUnknownVersion(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{"module": []string{"evil"}})
+	ensure.DeepEqual(t, env.Module, "all")
+	ensure.DeepEqual(t, env.SdkURL(), "http://connect.facebook.net/en_US/all.js")
+}
+
+func TestSdkURLEmptyVersion(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	env.Module = ""
+	ensure.DeepEqual(t, env.SdkURL(), "http://connect.facebook.net/en_US/all.js")
+}
+
+// Not parallel since it changes the log output.
+func TestSdkURLUnknownVersionWarns(t *testing.T) {
+	// The default slog logger writes using the log package.
+	var log bytes.Buffer
+	stdlog.SetOutput(&log)
+	defer stdlog.SetOutput(os.Stderr)
+
+	env, _ := fromValues(t, url.Values{})
+	env.Module = "foo"
+	ensure.DeepEqual(t, env.SdkURL(), "http://connect.facebook.net/en_US/all.js")
+	ensure.StringContains(t, log.String(), `unknown SDK version \"foo\"`)
+}
+
+func TestValidateUnknownSDKVersion(t *testing.T) {
+	t.Parallel()
+	env, _ := fromValues(t, url.Values{})
+	env.Module = "foo"
+	ensure.Err(t, env.Validate(), regexp.MustCompile(`unknown SDK version "foo"`))
+}
@@ -0,0 +1,58 @@
+package rellenv
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.signedrequest/fbsr"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+type contextSignedRequestKeyT int
+
+var contextSignedRequestKey = contextSignedRequestKeyT(1)
+
+// SignedRequestMiddleware verifies the signed_request parameter Facebook
+// passes to iframes, and stores the parsed request including the user ID,
+// locale and page data in the request context, where Parser.FromRequest uses
+// it for the Env. Requests without a signed_request are passed through
+// unchanged, and those with an invalid one or one older than maxAge result in
+// a 400.
+func SignedRequestMiddleware(appSecret string, maxAge time.Duration) func(http.Handler) http.Handler {
+	secret := []byte(appSecret)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := r.FormValue("signed_request")
+			if raw == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			sr, err := fbsr.Unmarshal([]byte(raw), secret, maxAge)
+			if err != nil {
+				http.Error(w, "invalid signed_request", http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithSignedRequest(r.Context(), sr)))
+		})
+	}
+}
+
+// WithSignedRequest adds the given signed request to the context.
+func WithSignedRequest(ctx context.Context, sr *fbsr.SignedRequest) context.Context {
+	return context.WithValue(ctx, contextSignedRequestKey, sr)
+}
+
+// The view mode for an iframe loaded with the signed request.
+func signedRequestViewMode(sr *fbsr.SignedRequest) string {
+	if sr.Page != nil {
+		return PageTab
+	}
+	return Canvas
+}
+
+// SignedRequestFromContext retrieves the signed request stored by
+// SignedRequestMiddleware.
+func SignedRequestFromContext(ctx context.Context) (*fbsr.SignedRequest, bool) {
+	sr, ok := ctx.Value(contextSignedRequestKey).(*fbsr.SignedRequest)
+	return sr, ok
+}
@@ -0,0 +1,102 @@
+package rellenv_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.signedrequest/fbsr"
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/rellenv"
+)
+
+const testAppSecret = "secret"
+
+func signRequest(secret, payload string) string {
+	p := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(p))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)) + "." + p
+}
+
+func serveSignedRequest(raw string) (*fbsr.SignedRequest, *httptest.ResponseRecorder) {
+	var seen *fbsr.SignedRequest
+	handler := rellenv.SignedRequestMiddleware(testAppSecret, time.Hour)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen, _ = rellenv.SignedRequestFromContext(r.Context())
+		}))
+	body := url.Values{}
+	if raw != "" {
+		body.Set("signed_request", raw)
+	}
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return seen, w
+}
+
+func TestSignedRequestMiddleware(t *testing.T) {
+	t.Parallel()
+	payload := fmt.Sprintf(
+		`{"algorithm":"HMAC-SHA256","issued_at":%d,"user_id":"42",`+
+			`"user":{"locale":"en_PI"},"page":{"id":"7","liked":true}}`,
+		time.Now().Unix())
+	sr, w := serveSignedRequest(signRequest(testAppSecret, payload))
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	ensure.DeepEqual(t, sr.UserID, uint64(42))
+	ensure.DeepEqual(t, sr.User.Locale, "en_PI")
+	ensure.DeepEqual(t, sr.Page.ID, uint64(7))
+	ensure.True(t, sr.Page.Liked)
+}
+
+func TestSignedRequestMiddlewareInvalidSignature(t *testing.T) {
+	t.Parallel()
+	payload := fmt.Sprintf(`{"issued_at":%d}`, time.Now().Unix())
+	sr, w := serveSignedRequest(signRequest("wrong", payload))
+	ensure.DeepEqual(t, w.Code, http.StatusBadRequest)
+	ensure.True(t, sr == nil)
+}
+
+func TestSignedRequestMiddlewarePassThrough(t *testing.T) {
+	t.Parallel()
+	sr, w := serveSignedRequest("")
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	ensure.True(t, sr == nil)
+}
+
+func TestSignedRequestMiddlewareExpired(t *testing.T) {
+	t.Parallel()
+	payload := fmt.Sprintf(`{"algorithm":"HMAC-SHA256","issued_at":%d}`,
+		time.Now().Add(-2*time.Hour).Unix())
+	sr, w := serveSignedRequest(signRequest(testAppSecret, payload))
+	ensure.DeepEqual(t, w.Code, http.StatusBadRequest)
+	ensure.True(t, sr == nil)
+}
+
+func TestSignedRequestMiddlewareEnv(t *testing.T) {
+	t.Parallel()
+	payload := fmt.Sprintf(
+		`{"algorithm":"HMAC-SHA256","issued_at":%d,"user_id":"42","page":{"id":"7"}}`,
+		time.Now().Unix())
+	var env *rellenv.Env
+	handler := rellenv.SignedRequestMiddleware(testAppSecret, time.Hour)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			env, err = defaultParser().FromRequest(r.Context(), r)
+			ensure.Nil(t, err)
+		}))
+	body := url.Values{"signed_request": {signRequest(testAppSecret, payload)}}
+	r := httptest.NewRequest("POST", "/", strings.NewReader(body.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	ensure.DeepEqual(t, env.SignedRequest.UserID, uint64(42))
+	ensure.DeepEqual(t, env.ViewMode, rellenv.PageTab)
+}
@@ -0,0 +1,40 @@
+package viewcontext_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/middleware"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	t.Parallel()
+	var log bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&log, nil))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("hello"))
+	})
+	r := httptest.NewRequest("POST", "/info/x", nil)
+	r.Header.Set("User-Agent", "test-agent")
+	r = r.WithContext(middleware.WithRequestID(r.Context(), "rid"))
+	viewcontext.AccessLogMiddleware(logger)(handler).ServeHTTP(httptest.NewRecorder(), r)
+
+	var entry map[string]interface{}
+	ensure.Nil(t, json.Unmarshal(log.Bytes(), &entry))
+	ensure.DeepEqual(t, entry["method"], "POST")
+	ensure.DeepEqual(t, entry["path"], "/info/x")
+	ensure.DeepEqual(t, entry["status"], float64(http.StatusTeapot))
+	ensure.DeepEqual(t, entry["bytes"], float64(5))
+	ensure.DeepEqual(t, entry["remote_addr"], r.RemoteAddr)
+	ensure.DeepEqual(t, entry["user_agent"], "test-agent")
+	ensure.DeepEqual(t, entry["request_id"], "rid")
+	_, ok := entry["duration_ms"]
+	ensure.True(t, ok)
+}
@@ -2,30 +2,207 @@
 package viewcontext
 
 import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
 	"net/http"
+	"path"
+	"runtime"
+	"runtime/debug"
 
 	"github.com/daaku/rell/internal/github.com/daaku/go.httpdev"
+	"github.com/daaku/rell/internal/github.com/facebookgo/fbapp"
 	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/middleware"
 	"github.com/daaku/rell/rellenv"
 )
 
 var rev string
 
-type Handler struct{}
+// SetRevision sets the revision reported by Info. This is for builds that do
+// not inject it using ldflags.
+func SetRevision(r string) {
+	rev = r
+}
+
+// BuildInfo describes the running binary.
+type BuildInfo struct {
+	Rev       string `json:"rev"`
+	GoVersion string `json:"goVersion"`
+	BuildTime string `json:"buildTime,omitempty"`
+	GoOS      string `json:"goOS"`
+	GoArch    string `json:"goArch"`
+}
+
+func buildInfo() BuildInfo {
+	info := BuildInfo{
+		Rev:       rev,
+		GoVersion: runtime.Version(),
+		GoOS:      runtime.GOOS,
+		GoArch:    runtime.GOARCH,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.time":
+				info.BuildTime = s.Value
+			case "GOOS":
+				info.GoOS = s.Value
+			case "GOARCH":
+				info.GoArch = s.Value
+			}
+		}
+	}
+	return info
+}
+
+type Handler struct {
+	// The application, whose ID and secret must be configured for the
+	// Healthz ready probe to succeed.
+	App fbapp.App
+
+	// Makes the Healthz ready probe fail if the revision is unknown. This is
+	// opt in since builds without bin/compile do not inject the revision.
+	ReadyRequiresRevision bool
+
+	// Origins allowed to make cross origin requests to Info. "*" allows any
+	// origin.
+	CORSOrigins []string
+
+	// Counters for the Metrics handler.
+	Counters *Counters
+
+	// Bearer token required by the Metrics handler, if set.
+	MetricsToken string
+
+	// Enables the Debug handler.
+	DebugEnabled bool
+
+	// Token required as the token query parameter by the Debug handler. The
+	// handler denies all requests if it is empty.
+	DebugToken string
+
+	// Context keys, by name, whose values are included by the Debug handler
+	// if they are strings or implement fmt.Stringer. Go contexts cannot be enumerated, so
+	// the keys must be listed.
+	DebugContextKeys map[string]interface{}
+}
+
+func (h *Handler) allowedOrigin(origin string) (string, bool) {
+	for _, o := range h.CORSOrigins {
+		if o == "*" {
+			return o, true
+		}
+		if o == origin {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// Reports if the response depends on the Origin, which is the case unless
+// CORS is disabled or every origin is allowed.
+func (h *Handler) corsVaries() bool {
+	if len(h.CORSOrigins) == 0 {
+		return false
+	}
+	for _, o := range h.CORSOrigins {
+		if o == "*" {
+			return false
+		}
+	}
+	return true
+}
 
-// Handler for /info/ to see a JSON view of some server context.
+// Sets the CORS headers if the Origin is allowed.
+func (h *Handler) cors(w http.ResponseWriter, r *http.Request) {
+	header := w.Header()
+	if h.corsVaries() {
+		header.Add("Vary", "Origin")
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+	allowed, ok := h.allowedOrigin(origin)
+	if !ok {
+		return
+	}
+	header.Set("Access-Control-Allow-Origin", allowed)
+	header.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	header.Set("Access-Control-Allow-Headers", "Content-Type")
+}
+
+// Buffers a response so its ETag can be computed from the exact bytes sent.
+type bufferedResponse struct {
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header         { return b.header }
+func (b *bufferedResponse) Write(p []byte) (int, error) { return b.body.Write(p) }
+func (b *bufferedResponse) WriteHeader(int)             {}
+
+// Handler for /info/ to see a JSON view of some server context. When the
+// revision is known the response only depends on the server context and the
+// Accept header, so it is cached using an ETag and leaves out the request
+// details and ID. Caching is disabled when the revision is unknown, since the
+// response may change without it.
 func (h *Handler) Info(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	h.Counters.infoRequest()
+	h.cors(w, r)
+	if r.Method == "OPTIONS" {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	if path.Clean(r.URL.Path) == "/info/env" {
+		return h.InfoEnv(ctx, w, r)
+	}
 	env, err := rellenv.FromContext(ctx)
 	if err != nil {
 		return err
 	}
 	info := map[string]interface{}{
-		"context":    env,
-		"pageTabURL": env.PageTabURL("/"),
-		"canvasURL":  env.CanvasURL("/"),
-		"sdkURL":     env.SdkURL(),
-		"rev":        rev,
+		"context": env,
+		"rev":     rev,
+		"build":   buildInfo(),
+	}
+	if b, err := rellenv.BundleFromContext(ctx); err == nil {
+		info["bundle"] = map[string]string{
+			"userID":    b.UserID,
+			"sessionID": b.SessionID,
+		}
+	}
+	w.Header().Add("Vary", "Accept")
+	if rev == "" {
+		info["requestID"] = middleware.RequestIDFromContext(r.Context())
+		httpdev.Info(info, w, r)
+		return nil
+	}
+
+	buf := &bufferedResponse{header: make(http.Header)}
+	httpdev.HumanJSON(info, buf, r)
+	etag := fmt.Sprintf(`"%x"`, md5.Sum(buf.body.Bytes()))
+	header := w.Header()
+	for k, vv := range buf.header {
+		header[k] = vv
+	}
+	header.Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	w.Write(buf.body.Bytes())
+	return nil
+}
+
+// Handler for /info/env to see only the public fields of the Env. Unlike Info
+// this excludes the signed request.
+func (h *Handler) InfoEnv(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	env, err := rellenv.FromContext(ctx)
+	if err != nil {
+		return err
 	}
-	httpdev.Info(info, w, r)
+	httpdev.HumanJSON(env.Public(), w, r)
 	return nil
 }
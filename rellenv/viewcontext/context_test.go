@@ -0,0 +1,172 @@
+package viewcontext_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.signedrequest/fbsr"
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/internal/github.com/facebookgo/fbapp"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/rellenv"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+func envContext() context.Context {
+	env := (&rellenv.Parser{App: fbapp.New(42, "", "")}).Default()
+	return rellenv.WithEnv(context.Background(), env)
+}
+
+func serveInfo(t *testing.T, h *viewcontext.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	ensure.Nil(t, h.Info(envContext(), w, r))
+	return w
+}
+
+func TestInfoCORSWildcard(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{CORSOrigins: []string{"*"}}
+	r := httptest.NewRequest("GET", "/info/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := serveInfo(t, h, r)
+	ensure.DeepEqual(t, w.Header().Get("Access-Control-Allow-Origin"), "*")
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+}
+
+func TestInfoCORSAllowedOrigin(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{CORSOrigins: []string{"https://a.com", "https://b.com"}}
+	r := httptest.NewRequest("GET", "/info/", nil)
+	r.Header.Set("Origin", "https://b.com")
+	w := serveInfo(t, h, r)
+	ensure.DeepEqual(t, w.Header().Get("Access-Control-Allow-Origin"), "https://b.com")
+	ensure.DeepEqual(t, w.Header().Get("Access-Control-Allow-Methods"), "GET, POST, OPTIONS")
+}
+
+func TestInfoCORSDisallowedOrigin(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{CORSOrigins: []string{"https://a.com"}}
+	r := httptest.NewRequest("GET", "/info/", nil)
+	r.Header.Set("Origin", "https://evil.com")
+	w := serveInfo(t, h, r)
+	ensure.DeepEqual(t, w.Header().Get("Access-Control-Allow-Origin"), "")
+	ensure.DeepEqual(t, w.Header().Get("Vary"), "Origin")
+}
+
+func TestInfoCORSVary(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{CORSOrigins: []string{"https://a.com"}}
+	w := serveInfo(t, h, httptest.NewRequest("GET", "/info/", nil))
+	ensure.DeepEqual(t, w.Header().Get("Vary"), "Origin")
+
+	h = &viewcontext.Handler{CORSOrigins: []string{"*"}}
+	r := httptest.NewRequest("GET", "/info/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w = serveInfo(t, h, r)
+	ensure.DeepEqual(t, w.Header().Values("Vary"), []string{"Accept"})
+}
+
+func TestInfoCORSPreflight(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{CORSOrigins: []string{"*"}}
+	r := httptest.NewRequest("OPTIONS", "/info/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	w := serveInfo(t, h, r)
+	ensure.DeepEqual(t, w.Code, http.StatusNoContent)
+	ensure.DeepEqual(t, w.Body.Len(), 0)
+}
+
+func TestInfoETag(t *testing.T) {
+	viewcontext.SetRevision("abc")
+	defer viewcontext.SetRevision("")
+	h := &viewcontext.Handler{}
+	w := serveInfo(t, h, httptest.NewRequest("GET", "/info/", nil))
+	etag := w.Header().Get("ETag")
+	ensure.True(t, etag != "")
+
+	r := httptest.NewRequest("GET", "/info/", nil)
+	r.Header.Set("If-None-Match", etag)
+	w = serveInfo(t, h, r)
+	ensure.DeepEqual(t, w.Code, http.StatusNotModified)
+}
+
+func TestInfoETagRepresentations(t *testing.T) {
+	viewcontext.SetRevision("abc")
+	defer viewcontext.SetRevision("")
+	h := &viewcontext.Handler{}
+	text := serveInfo(t, h, httptest.NewRequest("GET", "/info/?a=1", nil))
+	ensure.StringDoesNotContain(t, text.Body.String(), `"request"`)
+	ensure.StringDoesNotContain(t, text.Body.String(), `"requestID"`)
+	ensure.DeepEqual(t, text.Header().Get("Vary"), "Accept")
+
+	r := httptest.NewRequest("GET", "/info/?b=2", nil)
+	r.Header.Set("Accept", "text/html")
+	r.Header.Set("If-None-Match", text.Header().Get("ETag"))
+	html := serveInfo(t, h, r)
+	ensure.DeepEqual(t, html.Code, http.StatusOK)
+	ensure.True(t, html.Header().Get("ETag") != text.Header().Get("ETag"))
+
+	// the query does not change the cached representation
+	r = httptest.NewRequest("GET", "/info/?c=3", nil)
+	r.Header.Set("If-None-Match", text.Header().Get("ETag"))
+	ensure.DeepEqual(t, serveInfo(t, h, r).Code, http.StatusNotModified)
+}
+
+func TestInfoETagVariesWithEnv(t *testing.T) {
+	viewcontext.SetRevision("abc")
+	defer viewcontext.SetRevision("")
+	h := &viewcontext.Handler{}
+	w := serveInfo(t, h, httptest.NewRequest("GET", "/info/", nil))
+
+	env, _ := rellenv.FromContext(envContext())
+	other := httptest.NewRecorder()
+	ctx := rellenv.WithEnv(context.Background(), env.WithLocale("fr_FR"))
+	ensure.Nil(t, h.Info(ctx, other, httptest.NewRequest("GET", "/info/", nil)))
+	ensure.True(t, other.Header().Get("ETag") != w.Header().Get("ETag"))
+	ensure.StringContains(t, other.Body.String(), "/fr_FR/")
+}
+
+func TestInfoNoETagWithoutRevision(t *testing.T) {
+	w := serveInfo(t, &viewcontext.Handler{}, httptest.NewRequest("GET", "/info/", nil))
+	ensure.DeepEqual(t, w.Header().Get("ETag"), "")
+}
+
+func TestInfoBundle(t *testing.T) {
+	t.Parallel()
+	env, _ := rellenv.FromContext(envContext())
+	ctx := rellenv.WithBundle(context.Background(), &rellenv.Bundle{
+		Env:       env,
+		UserID:    "123",
+		SessionID: "abc",
+	})
+	w := httptest.NewRecorder()
+	h := &viewcontext.Handler{}
+	ensure.Nil(t, h.Info(ctx, w, httptest.NewRequest("GET", "/info/", nil)))
+
+	var actual struct {
+		Bundle map[string]string `json:"bundle"`
+	}
+	ensure.Nil(t, json.Unmarshal(w.Body.Bytes(), &actual))
+	ensure.DeepEqual(t, actual.Bundle, map[string]string{"userID": "123", "sessionID": "abc"})
+}
+
+func TestInfoEnv(t *testing.T) {
+	t.Parallel()
+	env, _ := rellenv.FromContext(envContext())
+	env.SignedRequest = &fbsr.SignedRequest{AccessToken: "secret-token", Code: "secret-code"}
+	ctx := rellenv.WithEnv(context.Background(), env)
+	w := httptest.NewRecorder()
+	h := &viewcontext.Handler{}
+	ensure.Nil(t, h.Info(ctx, w, httptest.NewRequest("GET", "/info/env", nil)))
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	ensure.StringDoesNotContain(t, w.Body.String(), "secret")
+
+	var actual rellenv.PublicEnv
+	ensure.Nil(t, json.Unmarshal(w.Body.Bytes(), &actual))
+	ensure.DeepEqual(t, actual, env.Public())
+	ensure.DeepEqual(t, actual.AppID, "42")
+	ensure.DeepEqual(t, actual.Locale, "en_US")
+	ensure.DeepEqual(t, actual.SDKVersion, "all")
+}
@@ -0,0 +1,66 @@
+package viewcontext
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.httpdev"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+// Headers and query parameters carrying credentials, which are redacted in
+// the /debug/ output.
+var (
+	debugRedactedHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+	debugRedactedParams  = []string{"token", "signed_request", "access_token", "code"}
+)
+
+// Handler for /debug/ to see a JSON view of the raw request. It returns a
+// 403 unless DebugEnabled is set and the request includes the DebugToken, which
+// must not be empty.
+func (h *Handler) Debug(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	token := r.URL.Query().Get("token")
+	if !h.DebugEnabled || h.DebugToken == "" ||
+		subtle.ConstantTimeCompare([]byte(token), []byte(h.DebugToken)) != 1 {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return nil
+	}
+
+	values := map[string]string{}
+	for name, key := range h.DebugContextKeys {
+		v := ctx.Value(key)
+		if v == nil {
+			v = r.Context().Value(key)
+		}
+		switch s := v.(type) {
+		case fmt.Stringer:
+			values[name] = s.String()
+		case string:
+			values[name] = s
+		}
+	}
+	headers := r.Header.Clone()
+	for _, name := range debugRedactedHeaders {
+		if _, ok := headers[name]; ok {
+			headers[name] = []string{"redacted"}
+		}
+	}
+	u := *r.URL
+	query := u.Query()
+	for _, name := range debugRedactedParams {
+		if _, ok := query[name]; ok {
+			query[name] = []string{"redacted"}
+		}
+	}
+	u.RawQuery = query.Encode()
+	httpdev.HumanJSON(map[string]interface{}{
+		"headers":    headers,
+		"remoteAddr": r.RemoteAddr,
+		"method":     r.Method,
+		"url":        u.String(),
+		"proto":      r.Proto,
+		"context":    values,
+	}, w, r)
+	return nil
+}
@@ -0,0 +1,86 @@
+package viewcontext_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/middleware"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+type debugKey int
+
+type stringer string
+
+func (s stringer) String() string { return string(s) }
+
+func TestDebugDisabled(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{}
+	w := httptest.NewRecorder()
+	ensure.Nil(t, h.Debug(context.Background(), w, httptest.NewRequest("GET", "/debug/", nil)))
+	ensure.DeepEqual(t, w.Code, http.StatusForbidden)
+}
+
+func TestDebugBadToken(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{DebugEnabled: true, DebugToken: "secret"}
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/debug/?token=wrong", nil)
+	ensure.Nil(t, h.Debug(context.Background(), w, r))
+	ensure.DeepEqual(t, w.Code, http.StatusForbidden)
+}
+
+func TestDebugEmptyToken(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{DebugEnabled: true}
+	w := httptest.NewRecorder()
+	ensure.Nil(t, h.Debug(context.Background(), w, httptest.NewRequest("GET", "/debug/", nil)))
+	ensure.DeepEqual(t, w.Code, http.StatusForbidden)
+}
+
+func TestDebug(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{
+		DebugEnabled: true,
+		DebugToken:   "secret",
+		DebugContextKeys: map[string]interface{}{
+			"stringer":  debugKey(1),
+			"plain":     debugKey(2),
+			"requestID": middleware.RequestIDKey,
+		},
+	}
+	ctx := context.WithValue(context.Background(), debugKey(1), stringer("yes"))
+	ctx = context.WithValue(ctx, debugKey(2), 42)
+	ctx = middleware.WithRequestID(ctx, "abc")
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/debug/?token=secret&signed_request=hunter2&a=b", nil)
+	r.Header.Set("X-Forwarded-For", "1.2.3.4")
+	r.Header.Set("Authorization", "Bearer hunter2")
+	r.Header.Set("Cookie", "session=hunter2")
+	ensure.Nil(t, h.Debug(ctx, w, r))
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+
+	var actual struct {
+		Headers    http.Header       `json:"headers"`
+		RemoteAddr string            `json:"remoteAddr"`
+		Method     string            `json:"method"`
+		URL        string            `json:"url"`
+		Proto      string            `json:"proto"`
+		Context    map[string]string `json:"context"`
+	}
+	ensure.Nil(t, json.Unmarshal(w.Body.Bytes(), &actual))
+	ensure.DeepEqual(t, actual.Headers.Get("X-Forwarded-For"), "1.2.3.4")
+	ensure.DeepEqual(t, actual.Headers.Get("Authorization"), "redacted")
+	ensure.DeepEqual(t, actual.Headers.Get("Cookie"), "redacted")
+	ensure.StringDoesNotContain(t, w.Body.String(), "hunter2")
+	ensure.DeepEqual(t, actual.RemoteAddr, r.RemoteAddr)
+	ensure.DeepEqual(t, actual.Method, "GET")
+	ensure.DeepEqual(t, actual.URL, "/debug/?a=b&signed_request=redacted&token=redacted")
+	ensure.DeepEqual(t, actual.Proto, "HTTP/1.1")
+	ensure.DeepEqual(t, actual.Context, map[string]string{"stringer": "yes", "requestID": "abc"})
+}
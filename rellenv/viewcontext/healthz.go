@@ -0,0 +1,54 @@
+package viewcontext
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/rellenv"
+)
+
+// Returns an error if the server is not configured to serve requests or the
+// Env cannot be loaded from the context.
+func (h *Handler) ready(ctx context.Context) error {
+	if _, err := rellenv.FromContext(ctx); err != nil {
+		return err
+	}
+	if h.App == nil || h.App.ID() == 0 {
+		return errors.New("missing app ID")
+	}
+	if h.App.Secret() == "" {
+		return errors.New("missing app secret")
+	}
+	if h.ReadyRequiresRevision && rev == "" {
+		return errors.New("missing revision")
+	}
+	return nil
+}
+
+// Handler for /healthz/live and /healthz/ready probes. The probe type is
+// taken from the type query parameter, or the last path segment. Liveness
+// always succeeds, while readiness requires the Env in the context, the App ID
+// and secret, and the revision if ReadyRequiresRevision is set.
+func (h *Handler) Healthz(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	probe := r.URL.Query().Get("type")
+	if probe == "" {
+		probe = path.Base(r.URL.Path)
+	}
+	switch probe {
+	case "live":
+	case "ready":
+		if err := h.ready(ctx); err != nil {
+			http.Error(w, "not ready: "+err.Error(), http.StatusServiceUnavailable)
+			return nil
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unknown probe type %q", probe), http.StatusBadRequest)
+		return nil
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, "ok")
+	return nil
+}
@@ -0,0 +1,77 @@
+package viewcontext_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/internal/github.com/facebookgo/fbapp"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+func serveHealthz(t *testing.T, h *viewcontext.Handler, url string) *httptest.ResponseRecorder {
+	return serveHealthzContext(t, envContext(), h, url)
+}
+
+func serveHealthzContext(t *testing.T, ctx context.Context, h *viewcontext.Handler, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	ensure.Nil(t, h.Healthz(ctx, w, httptest.NewRequest("GET", url, nil)))
+	return w
+}
+
+func readyHandler() *viewcontext.Handler {
+	return &viewcontext.Handler{App: fbapp.New(42, "secret", "")}
+}
+
+func TestHealthzLive(t *testing.T) {
+	t.Parallel()
+	w := serveHealthz(t, &viewcontext.Handler{}, "/healthz/live")
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	ensure.DeepEqual(t, w.Body.String(), "ok")
+}
+
+func TestHealthzReady(t *testing.T) {
+	t.Parallel()
+	w := serveHealthz(t, readyHandler(), "/healthz/ready")
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	ensure.DeepEqual(t, w.Body.String(), "ok")
+}
+
+func TestHealthzNotReady(t *testing.T) {
+	t.Parallel()
+	w := serveHealthz(t, &viewcontext.Handler{}, "/healthz/ready")
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	ensure.StringContains(t, w.Body.String(), "missing app ID")
+
+	h := &viewcontext.Handler{App: fbapp.New(42, "", "")}
+	w = serveHealthz(t, h, "/healthz/ready")
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	ensure.StringContains(t, w.Body.String(), "missing app secret")
+
+	w = serveHealthzContext(t, context.Background(), readyHandler(), "/healthz/ready")
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	ensure.StringContains(t, w.Body.String(), "Env not found")
+}
+
+func TestHealthzReadyRequiresRevision(t *testing.T) {
+	h := readyHandler()
+	h.ReadyRequiresRevision = true
+	w := serveHealthz(t, h, "/healthz/ready")
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	ensure.StringContains(t, w.Body.String(), "missing revision")
+
+	viewcontext.SetRevision("abc")
+	defer viewcontext.SetRevision("")
+	w = serveHealthz(t, h, "/healthz/ready")
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+}
+
+func TestHealthzTypeParam(t *testing.T) {
+	t.Parallel()
+	w := serveHealthz(t, &viewcontext.Handler{}, "/healthz/live?type=ready")
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	w = serveHealthz(t, &viewcontext.Handler{}, "/healthz/other")
+	ensure.DeepEqual(t, w.Code, http.StatusBadRequest)
+}
@@ -0,0 +1,74 @@
+package viewcontext
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+)
+
+// Counters are exposed by the Metrics handler. A nil Counters ignores all
+// updates.
+type Counters struct {
+	requests     atomic.Uint64
+	errors       atomic.Uint64
+	infoRequests atomic.Uint64
+}
+
+// Request counts a HTTP request.
+func (c *Counters) Request() {
+	if c != nil {
+		c.requests.Add(1)
+	}
+}
+
+// Error counts a HTTP request that resulted in an error.
+func (c *Counters) Error() {
+	if c != nil {
+		c.errors.Add(1)
+	}
+}
+
+func (c *Counters) infoRequest() {
+	if c != nil {
+		c.infoRequests.Add(1)
+	}
+}
+
+func writeCounter(w http.ResponseWriter, name, help string, value uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, value)
+}
+
+// Handler for /metrics to expose counters in the Prometheus text format. If
+// MetricsToken is set, it must be provided as a bearer token.
+func (h *Handler) Metrics(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if h.MetricsToken != "" {
+		const prefix = "Bearer "
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, prefix) ||
+			subtle.ConstantTimeCompare([]byte(auth[len(prefix):]), []byte(h.MetricsToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return nil
+		}
+	}
+
+	c := h.Counters
+	if c == nil {
+		c = &Counters{}
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeCounter(w, "rell_requests_total", "Total HTTP requests.", c.requests.Load())
+	writeCounter(w, "rell_errors_total", "Total HTTP requests resulting in an error.", c.errors.Load())
+	writeCounter(w, "rell_info_requests_total", "Total requests to /info/.", c.infoRequests.Load())
+
+	info := buildInfo()
+	fmt.Fprintf(w,
+		"# HELP rell_build_info Build information.\n# TYPE rell_build_info gauge\n"+
+			"rell_build_info{rev=%q,go_version=%q} 1\n",
+		info.Rev, info.GoVersion)
+	return nil
+}
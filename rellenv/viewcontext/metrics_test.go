@@ -0,0 +1,50 @@
+package viewcontext_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+func serveMetrics(t *testing.T, h *viewcontext.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	ensure.Nil(t, h.Metrics(context.Background(), w, r))
+	return w
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{Counters: &viewcontext.Counters{}}
+	h.Counters.Request()
+	h.Counters.Request()
+	h.Counters.Error()
+	serveInfo(t, h, httptest.NewRequest("GET", "/info/", nil))
+	w := serveMetrics(t, h, httptest.NewRequest("GET", "/metrics", nil))
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	body := w.Body.String()
+	ensure.StringContains(t, body, "# TYPE rell_requests_total counter\nrell_requests_total 2\n")
+	ensure.StringContains(t, body, "\nrell_errors_total 1\n")
+	ensure.StringContains(t, body, "\nrell_info_requests_total 1\n")
+}
+
+func TestMetricsToken(t *testing.T) {
+	t.Parallel()
+	h := &viewcontext.Handler{MetricsToken: "secret"}
+	w := serveMetrics(t, h, httptest.NewRequest("GET", "/metrics", nil))
+	ensure.DeepEqual(t, w.Code, http.StatusUnauthorized)
+
+	r := httptest.NewRequest("GET", "/metrics", nil)
+	r.Header.Set("Authorization", "Bearer wrong")
+	w = serveMetrics(t, h, r)
+	ensure.DeepEqual(t, w.Code, http.StatusUnauthorized)
+
+	r = httptest.NewRequest("GET", "/metrics", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	w = serveMetrics(t, h, r)
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	ensure.StringContains(t, w.Body.String(), "rell_requests_total 0")
+}
@@ -0,0 +1,102 @@
+package viewcontext
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.trustforward"
+	"github.com/daaku/rell/internal/golang.org/x/time/rate"
+)
+
+// Limiters that have not been used for this long are evicted.
+const rateLimitIdle = time.Hour
+
+type ipLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64
+}
+
+type rateLimiter struct {
+	rps       rate.Limit
+	burst     int
+	limiters  sync.Map // ip => *ipLimiter
+	lastSweep atomic.Int64
+}
+
+// Return the limiter for the IP, creating it if necessary.
+func (l *rateLimiter) get(ip string, now time.Time) *rate.Limiter {
+	v, ok := l.limiters.Load(ip)
+	if !ok {
+		v, _ = l.limiters.LoadOrStore(ip, &ipLimiter{
+			limiter: rate.NewLimiter(l.rps, l.burst),
+		})
+	}
+	il := v.(*ipLimiter)
+	il.lastSeen.Store(now.UnixNano())
+	return il.limiter
+}
+
+// Evict idle limiters, at most once per minute.
+func (l *rateLimiter) sweep(now time.Time) {
+	last := l.lastSweep.Load()
+	if now.UnixNano()-last < int64(time.Minute) ||
+		!l.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	cutoff := now.Add(-rateLimitIdle).UnixNano()
+	l.limiters.Range(func(k, v interface{}) bool {
+		if v.(*ipLimiter).lastSeen.Load() < cutoff {
+			l.limiters.Delete(k)
+		}
+		return true
+	})
+}
+
+// Returns the client IP using the trusted forwarding headers. For
+// X-Forwarded-For only the last entry is used, since it is the one appended by
+// our proxy while earlier entries are sent by the client. Without trusted
+// forwarding the connection address is used.
+func clientIP(r *http.Request, forwarded *trustforward.Forwarded) string {
+	remote := r.RemoteAddr
+	if forwarded != nil {
+		remote = forwarded.Remote(r)
+	}
+	remote = strings.TrimSpace(remote[strings.LastIndex(remote, ",")+1:])
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		return host
+	}
+	return remote
+}
+
+// RateLimitMiddleware limits each client IP to rps requests per second with
+// the given burst. Requests over the limit get a 429 with a Retry-After
+// header. The client IP is taken from the forwarding headers trusted by
+// forwarded, which may be nil to only use the connection address.
+func RateLimitMiddleware(rps float64, burst int, forwarded *trustforward.Forwarded) func(http.Handler) http.Handler {
+	l := &rateLimiter{rps: rate.Limit(rps), burst: burst}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := time.Now()
+			l.sweep(now)
+			res := l.get(clientIP(r, forwarded), now).ReserveN(now, 1)
+			if delay := res.DelayFrom(now); !res.OK() || delay > 0 {
+				res.CancelAt(now)
+				retry := 1
+				if res.OK() {
+					retry = int(math.Ceil(delay.Seconds()))
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retry))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests),
+					http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
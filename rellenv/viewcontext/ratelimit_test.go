@@ -0,0 +1,99 @@
+package viewcontext_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/daaku/go.trustforward"
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+func rateLimitRequest(h http.Handler, remote, forwarded string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("GET", "/info/", nil)
+	r.RemoteAddr = remote
+	if forwarded != "" {
+		r.Header.Set("X-Forwarded-For", forwarded)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+var trusted = &trustforward.Forwarded{X: true}
+
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := viewcontext.RateLimitMiddleware(0.001, 2, nil)(ok)
+
+	ensure.DeepEqual(t, rateLimitRequest(h, "1.1.1.1:1", "").Code, http.StatusOK)
+	ensure.DeepEqual(t, rateLimitRequest(h, "1.1.1.1:2", "").Code, http.StatusOK)
+	w := rateLimitRequest(h, "1.1.1.1:3", "")
+	ensure.DeepEqual(t, w.Code, http.StatusTooManyRequests)
+	ensure.True(t, w.Header().Get("Retry-After") != "")
+
+	// other clients have their own bucket
+	ensure.DeepEqual(t, rateLimitRequest(h, "2.2.2.2:1", "").Code, http.StatusOK)
+}
+
+func TestRateLimitForwarded(t *testing.T) {
+	t.Parallel()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := viewcontext.RateLimitMiddleware(0.001, 1, trusted)(ok)
+
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "10.0.0.1:1", "3.3.3.3").Code, http.StatusOK)
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "10.0.0.1:1", "3.3.3.3").Code, http.StatusTooManyRequests)
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "10.0.0.1:1", "4.4.4.4").Code, http.StatusOK)
+}
+
+func TestRateLimitSpoofedForwarded(t *testing.T) {
+	t.Parallel()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := viewcontext.RateLimitMiddleware(0.001, 1, trusted)(ok)
+
+	// the client controls everything before the hop appended by the proxy
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "10.0.0.1:1", "5.5.5.5, 3.3.3.3").Code, http.StatusOK)
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "10.0.0.1:1", "6.6.6.6, 3.3.3.3").Code, http.StatusTooManyRequests)
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "10.0.0.1:1", "3.3.3.3").Code, http.StatusTooManyRequests)
+}
+
+func TestRateLimitUntrustedForwarded(t *testing.T) {
+	t.Parallel()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := viewcontext.RateLimitMiddleware(0.001, 1, &trustforward.Forwarded{})(ok)
+
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "7.7.7.7:1", "3.3.3.3").Code, http.StatusOK)
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "7.7.7.7:2", "4.4.4.4").Code, http.StatusTooManyRequests)
+	ensure.DeepEqual(t,
+		rateLimitRequest(h, "8.8.8.8:1", "3.3.3.3").Code, http.StatusOK)
+}
+
+func TestRateLimitCloudFlare(t *testing.T) {
+	t.Parallel()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	h := viewcontext.RateLimitMiddleware(0.001, 1,
+		&trustforward.Forwarded{X: true, CloudFlare: true})(ok)
+
+	request := func(visitor, forwarded string) int {
+		r := httptest.NewRequest("GET", "/info/", nil)
+		r.Header.Set("Cf-Connecting-Ip", visitor)
+		r.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+	// the last hop is a CloudFlare edge shared by many visitors
+	ensure.DeepEqual(t, request("5.5.5.5", "5.5.5.5, 9.9.9.9"), http.StatusOK)
+	ensure.DeepEqual(t, request("6.6.6.6", "6.6.6.6, 9.9.9.9"), http.StatusOK)
+	ensure.DeepEqual(t, request("5.5.5.5", "5.5.5.5, 9.9.9.9"), http.StatusTooManyRequests)
+}
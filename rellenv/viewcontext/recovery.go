@@ -0,0 +1,62 @@
+package viewcontext
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/daaku/rell/middleware"
+)
+
+// Tracks if the response header has been sent.
+type headerTracker struct {
+	http.ResponseWriter
+	sent bool
+}
+
+func (t *headerTracker) WriteHeader(code int) {
+	t.sent = true
+	t.ResponseWriter.WriteHeader(code)
+}
+
+func (t *headerTracker) Write(b []byte) (int, error) {
+	t.sent = true
+	return t.ResponseWriter.Write(b)
+}
+
+// RecoveryMiddleware recovers from panics in the handler, logging the value
+// and stack trace, including the original stack of panics raised again by
+// TimeoutMiddleware. A 500 response is written unless the handler already sent
+// the response header.
+func RecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tracker := &headerTracker{ResponseWriter: w}
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				stack := debug.Stack()
+				if p, ok := v.(*handlerPanic); ok {
+					v, stack = p.value, p.stack
+				}
+				logger.Error("panic serving request",
+					"panic", v,
+					"method", r.Method,
+					"path", r.URL.Path,
+					"request_id", middleware.RequestIDFromContext(r.Context()),
+					"stack", string(stack),
+				)
+				if !tracker.sent {
+					http.Error(w, http.StatusText(http.StatusInternalServerError),
+						http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(tracker, r)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package viewcontext_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+func serveRecovery(handler http.HandlerFunc) (*httptest.ResponseRecorder, string) {
+	var log bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&log, nil))
+	w := httptest.NewRecorder()
+	viewcontext.RecoveryMiddleware(logger)(handler).ServeHTTP(
+		w, httptest.NewRequest("GET", "/boom", nil))
+	return w, log.String()
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	t.Parallel()
+	w, log := serveRecovery(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	ensure.DeepEqual(t, w.Code, http.StatusInternalServerError)
+	ensure.StringContains(t, log, "panic=boom")
+	ensure.StringContains(t, log, "path=/boom")
+	ensure.StringContains(t, log, "recovery_test.go")
+}
+
+func TestRecoveryMiddlewareHeaderSent(t *testing.T) {
+	t.Parallel()
+	w, log := serveRecovery(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("partial"))
+		panic("late")
+	})
+	ensure.DeepEqual(t, w.Code, http.StatusAccepted)
+	ensure.DeepEqual(t, w.Body.String(), "partial")
+	ensure.StringContains(t, log, "panic=late")
+}
+
+func TestRecoveryMiddlewareNoPanic(t *testing.T) {
+	t.Parallel()
+	w, log := serveRecovery(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	ensure.DeepEqual(t, w.Code, http.StatusOK)
+	ensure.DeepEqual(t, log, "")
+}
+
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	panic("boom")
+}
+
+func TestRecoveryMiddlewareTimeoutStack(t *testing.T) {
+	t.Parallel()
+	w, log := serveRecovery(func(w http.ResponseWriter, r *http.Request) {
+		viewcontext.TimeoutMiddleware(time.Second)(
+			http.HandlerFunc(panickingHandler)).ServeHTTP(w, r)
+	})
+	ensure.DeepEqual(t, w.Code, http.StatusInternalServerError)
+	ensure.StringContains(t, log, "panic=boom")
+	ensure.StringContains(t, log, "panickingHandler")
+}
+
+// A buffer that is safe to read while the log package writes to it.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+// Not parallel since it changes the log output.
+func TestTimeoutMiddlewareLatePanic(t *testing.T) {
+	// The default slog logger writes using the log package.
+	var log syncBuffer
+	stdlog.SetOutput(&log)
+	defer stdlog.SetOutput(os.Stderr)
+
+	panicked := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		defer close(panicked)
+		panickingHandler(w, r)
+	})
+	w := httptest.NewRecorder()
+	viewcontext.TimeoutMiddleware(10*time.Millisecond)(slow).ServeHTTP(
+		w, httptest.NewRequest("GET", "/", nil))
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	<-panicked
+	// the panic is logged by a deferred function after close
+	for i := 0; i < 100 && !strings.Contains(log.String(), "panickingHandler"); i++ {
+		time.Sleep(time.Millisecond)
+	}
+	ensure.StringContains(t, log.String(), "after timeout")
+	ensure.StringContains(t, log.String(), "panickingHandler")
+}
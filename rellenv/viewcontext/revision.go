@@ -0,0 +1,14 @@
+package viewcontext
+
+import "log/slog"
+
+// Verify the build script injects the revision.
+//go:generate sh -c "grep -q -- '-X .*/rellenv/viewcontext.rev' ../../bin/compile || { echo bin/compile does not inject viewcontext.rev; exit 1; }"
+
+// RequireRevision warns if the revision is unknown in production, which
+// means the binary was not built with the revision injected using ldflags.
+func RequireRevision(isProd bool) {
+	if isProd && rev == "" {
+		slog.Warn("viewcontext: revision is empty, build with -ldflags \"-X github.com/daaku/rell/rellenv/viewcontext.rev=<rev>\"")
+	}
+}
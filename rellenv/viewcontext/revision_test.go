@@ -0,0 +1,31 @@
+package viewcontext_test
+
+import (
+	"bytes"
+	stdlog "log"
+	"os"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+// Not parallel since it changes the log output and the revision.
+func TestRequireRevision(t *testing.T) {
+	// The default slog logger writes using the log package.
+	var log bytes.Buffer
+	stdlog.SetOutput(&log)
+	defer stdlog.SetOutput(os.Stderr)
+
+	viewcontext.RequireRevision(false)
+	ensure.DeepEqual(t, log.String(), "")
+
+	viewcontext.RequireRevision(true)
+	ensure.StringContains(t, log.String(), "revision is empty")
+
+	log.Reset()
+	viewcontext.SetRevision("abc")
+	defer viewcontext.SetRevision("")
+	viewcontext.RequireRevision(true)
+	ensure.DeepEqual(t, log.String(), "")
+}
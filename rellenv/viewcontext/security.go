@@ -0,0 +1,36 @@
+package viewcontext
+
+import "net/http"
+
+// SecurityHeadersOptions configures the headers set by
+// SecurityHeadersMiddleware. Empty values are not set.
+type SecurityHeadersOptions struct {
+	StrictTransportSecurity string // e.g. "max-age=31536000; includeSubDomains"
+	ContentSecurityPolicy   string
+	XFrameOptions           string // e.g. "DENY"
+	XContentTypeOptions     string // e.g. "nosniff"
+	ReferrerPolicy          string // e.g. "strict-origin-when-cross-origin"
+}
+
+// SecurityHeadersMiddleware sets the configured security headers on every
+// response.
+func SecurityHeadersMiddleware(opts SecurityHeadersOptions) func(http.Handler) http.Handler {
+	headers := [][2]string{
+		{"Strict-Transport-Security", opts.StrictTransportSecurity},
+		{"Content-Security-Policy", opts.ContentSecurityPolicy},
+		{"X-Frame-Options", opts.XFrameOptions},
+		{"X-Content-Type-Options", opts.XContentTypeOptions},
+		{"Referrer-Policy", opts.ReferrerPolicy},
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			for _, h := range headers {
+				if h[1] != "" {
+					header.Set(h[0], h[1])
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
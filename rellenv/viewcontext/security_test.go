@@ -0,0 +1,52 @@
+package viewcontext_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+var securityHeaders = []string{
+	"Strict-Transport-Security",
+	"Content-Security-Policy",
+	"X-Frame-Options",
+	"X-Content-Type-Options",
+	"Referrer-Policy",
+}
+
+func serveSecurityHeaders(opts viewcontext.SecurityHeadersOptions) http.Header {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	w := httptest.NewRecorder()
+	viewcontext.SecurityHeadersMiddleware(opts)(ok).ServeHTTP(
+		w, httptest.NewRequest("GET", "/info/", nil))
+	return w.Header()
+}
+
+func TestSecurityHeadersConfigured(t *testing.T) {
+	t.Parallel()
+	header := serveSecurityHeaders(viewcontext.SecurityHeadersOptions{
+		StrictTransportSecurity: "max-age=60",
+		ContentSecurityPolicy:   "default-src 'self'",
+		XFrameOptions:           "DENY",
+		XContentTypeOptions:     "nosniff",
+		ReferrerPolicy:          "no-referrer",
+	})
+	ensure.DeepEqual(t, header.Get("Strict-Transport-Security"), "max-age=60")
+	ensure.DeepEqual(t, header.Get("Content-Security-Policy"), "default-src 'self'")
+	ensure.DeepEqual(t, header.Get("X-Frame-Options"), "DENY")
+	ensure.DeepEqual(t, header.Get("X-Content-Type-Options"), "nosniff")
+	ensure.DeepEqual(t, header.Get("Referrer-Policy"), "no-referrer")
+}
+
+func TestSecurityHeadersZeroValue(t *testing.T) {
+	t.Parallel()
+	header := serveSecurityHeaders(viewcontext.SecurityHeadersOptions{})
+	for _, name := range securityHeaders {
+		if _, ok := header[name]; ok {
+			t.Fatalf("did not expect header %s", name)
+		}
+	}
+}
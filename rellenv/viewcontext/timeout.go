@@ -0,0 +1,168 @@
+package viewcontext
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/middleware"
+)
+
+// Buffers the response so it can be discarded if the handler times out.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (t *timeoutWriter) Header() http.Header {
+	return t.header
+}
+
+func (t *timeoutWriter) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if t.code == 0 {
+		t.code = http.StatusOK
+	}
+	return t.buf.Write(b)
+}
+
+func (t *timeoutWriter) WriteHeader(code int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timedOut || t.code != 0 {
+		return
+	}
+	t.code = code
+}
+
+// Takes the deadline and cancellation from the request context and values from
+// the base context first, then the request context.
+type requestContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c requestContext) Value(key interface{}) interface{} {
+	if v := c.base.Value(key); v != nil {
+		return v
+	}
+	return c.Context.Value(key)
+}
+
+// RequestContext returns a context with the values of base that is canceled
+// along with the request context, including by TimeoutMiddleware. It is for
+// handlers that are given a context not derived from the request.
+func RequestContext(base context.Context, r *http.Request) context.Context {
+	return requestContext{Context: r.Context(), base: base}
+}
+
+// A panic recovered from the handler goroutine, with the stack trace where it
+// happened. RecoveryMiddleware logs this stack rather than its own.
+type handlerPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p *handlerPanic) String() string {
+	return fmt.Sprint(p.value)
+}
+
+// TimeoutMiddleware cancels the request context after d. If the handler has
+// not completed by then, a 503 with a Retry-After header is sent and anything
+// the handler writes afterwards is discarded. The response is buffered in
+// memory until the handler completes, so it does not implement http.Flusher
+// and should not wrap large or streaming responses. A handler panic is
+// raised again with its stack trace for RecoveryMiddleware, or logged if it
+// happens after the timeout.
+func TimeoutMiddleware(d time.Duration) func(http.Handler) http.Handler {
+	retryAfter := strconv.Itoa(int(math.Max(1, math.Ceil(d.Seconds()))))
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			r = r.WithContext(ctx)
+
+			tw := &timeoutWriter{header: make(http.Header)}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					v := recover()
+					if v == nil {
+						return
+					}
+					if v != http.ErrAbortHandler {
+						v = &handlerPanic{value: v, stack: debug.Stack()}
+					}
+					tw.mu.Lock()
+					defer tw.mu.Unlock()
+					if tw.timedOut {
+						logLatePanic(r, v)
+						return
+					}
+					panicked <- v
+				}()
+				next.ServeHTTP(tw, r)
+				close(done)
+			}()
+
+			select {
+			case v := <-panicked:
+				panic(v)
+			case <-done:
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				dst := w.Header()
+				for k, vv := range tw.header {
+					dst[k] = vv
+				}
+				if tw.code == 0 {
+					tw.code = http.StatusOK
+				}
+				w.WriteHeader(tw.code)
+				w.Write(tw.buf.Bytes())
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				// the handler may have panicked just as the timeout fired
+				select {
+				case v := <-panicked:
+					logLatePanic(r, v)
+				default:
+				}
+				w.Header().Set("Retry-After", retryAfter)
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable),
+					http.StatusServiceUnavailable)
+			}
+		})
+	}
+}
+
+// Log a handler panic that happened after the timeout response was sent.
+func logLatePanic(r *http.Request, v interface{}) {
+	attrs := []interface{}{
+		"panic", v,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"request_id", middleware.RequestIDFromContext(r.Context()),
+	}
+	if p, ok := v.(*handlerPanic); ok {
+		attrs = append(attrs, "stack", string(p.stack))
+	}
+	slog.Error("panic serving request after timeout", attrs...)
+}
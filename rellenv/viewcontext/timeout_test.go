@@ -0,0 +1,65 @@
+package viewcontext_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/daaku/rell/internal/github.com/facebookgo/ensure"
+	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/rellenv/viewcontext"
+)
+
+func TestTimeoutMiddlewareTimesOut(t *testing.T) {
+	t.Parallel()
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		w.Write([]byte("late"))
+	})
+	w := httptest.NewRecorder()
+	viewcontext.TimeoutMiddleware(10*time.Millisecond)(slow).ServeHTTP(
+		w, httptest.NewRequest("GET", "/", nil))
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	ensure.DeepEqual(t, w.Header().Get("Retry-After"), "1")
+	ensure.StringDoesNotContain(t, w.Body.String(), "late")
+}
+
+func TestTimeoutMiddlewareCompletes(t *testing.T) {
+	t.Parallel()
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Fast", "1")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("done"))
+	})
+	w := httptest.NewRecorder()
+	viewcontext.TimeoutMiddleware(time.Second)(fast).ServeHTTP(
+		w, httptest.NewRequest("GET", "/", nil))
+	ensure.DeepEqual(t, w.Code, http.StatusCreated)
+	ensure.DeepEqual(t, w.Header().Get("X-Fast"), "1")
+	ensure.DeepEqual(t, w.Header().Get("Retry-After"), "")
+	ensure.DeepEqual(t, w.Body.String(), "done")
+}
+
+func TestRequestContextObservesTimeout(t *testing.T) {
+	t.Parallel()
+	base := context.WithValue(context.Background(), debugKey(1), "base")
+	observed := make(chan interface{}, 1)
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := viewcontext.RequestContext(base, r)
+		select {
+		case <-time.After(time.Second):
+			observed <- nil
+		case <-ctx.Done():
+			observed <- ctx.Value(debugKey(1))
+		}
+	})
+	w := httptest.NewRecorder()
+	viewcontext.TimeoutMiddleware(10*time.Millisecond)(slow).ServeHTTP(
+		w, httptest.NewRequest("GET", "/", nil))
+	ensure.DeepEqual(t, w.Code, http.StatusServiceUnavailable)
+	ensure.DeepEqual(t, <-observed, "base")
+}
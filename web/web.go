@@ -2,9 +2,13 @@
 package web
 
 import (
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"path"
+	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
@@ -16,6 +20,7 @@ import (
 	"github.com/daaku/rell/internal/github.com/daaku/go.static"
 	"github.com/daaku/rell/internal/github.com/facebookgo/fbapp"
 	"github.com/daaku/rell/internal/golang.org/x/net/context"
+	"github.com/daaku/rell/middleware"
 	"github.com/daaku/rell/oauth"
 	"github.com/daaku/rell/og/viewog"
 	"github.com/daaku/rell/rellenv"
@@ -37,6 +42,17 @@ type Handler struct {
 	OauthHandler    *oauth.Handler
 	Static          *static.Handler
 	AdminHandler    *adminweb.Handler
+	SecurityHeaders viewcontext.SecurityHeadersOptions
+
+	// Per IP rate limit for /info/, disabled if zero.
+	InfoRateLimit float64
+	InfoRateBurst int
+
+	// Timeout for handling a request, disabled if zero.
+	RequestTimeout time.Duration
+
+	// Logger for the access log, disabled if nil.
+	AccessLog *slog.Logger
 
 	ctx  context.Context
 	mux  http.Handler
@@ -52,6 +68,7 @@ func (a *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		mux, err := ctxmux.New(
 			ctxmux.MuxErrorHandler(a.handleError),
+			ctxmux.MuxPanicHandler(a.handlePanic),
 			ctxmux.MuxNotFoundHandler(a.ExamplesHandler.Example),
 			ctxmux.MuxRedirectTrailingSlash(),
 			ctxmux.MuxContextMaker(a.contextMaker),
@@ -67,6 +84,11 @@ func (a *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		mux.GET(public+"*rest", ctxmux.HTTPHandler(http.StripPrefix(public, fileserver)))
 		mux.GET("/info/*rest", a.ContextHandler.Info)
 		mux.POST("/info/*rest", a.ContextHandler.Info)
+		mux.Handler("OPTIONS", "/info/*rest", a.ContextHandler.Info)
+		mux.GET("/metrics", a.ContextHandler.Metrics)
+		mux.GET("/debug/*rest", a.ContextHandler.Debug)
+		mux.GET("/healthz/live", a.ContextHandler.Healthz)
+		mux.GET("/healthz/ready", a.ContextHandler.Healthz)
 		mux.GET("/examples/", a.ExamplesHandler.List)
 		mux.GET("/saved/:hash", a.ExamplesHandler.GetSaved)
 		mux.POST("/saved/", a.ExamplesHandler.PostSaved)
@@ -86,6 +108,38 @@ func (a *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			Secret:  a.App.SecretByte(),
 			MaxAge:  a.SignedRequestMaxAge,
 		}
+		handler = rellenv.SignedRequestMiddleware(a.App.Secret(), a.SignedRequestMaxAge)(handler)
+		if a.InfoRateLimit > 0 {
+			limited := viewcontext.RateLimitMiddleware(
+				a.InfoRateLimit, a.InfoRateBurst, a.EnvParser.Forwarded)(handler)
+			unlimited := handler
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.Path, "/info/") {
+					limited.ServeHTTP(w, r)
+					return
+				}
+				unlimited.ServeHTTP(w, r)
+			})
+		}
+		if a.RequestTimeout > 0 {
+			// static files are not buffered by the timeout
+			timed := viewcontext.TimeoutMiddleware(a.RequestTimeout)(handler)
+			untimed := handler
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.Path, a.Static.Path) ||
+					strings.HasPrefix(r.URL.Path, public) {
+					untimed.ServeHTTP(w, r)
+					return
+				}
+				timed.ServeHTTP(w, r)
+			})
+		}
+		handler = viewcontext.RecoveryMiddleware(slog.Default())(handler)
+		handler = viewcontext.SecurityHeadersMiddleware(a.SecurityHeaders)(handler)
+		if a.AccessLog != nil {
+			handler = viewcontext.AccessLogMiddleware(a.AccessLog)(handler)
+		}
+		handler = middleware.RequestIDMiddleware(handler)
 		a.mux = handler
 
 		a.ctx = context.Background()
@@ -94,18 +148,32 @@ func (a *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			StringMode: ctxerr.StringModeNone,
 		})
 	})
+	a.ContextHandler.Counters.Request()
 	a.mux.ServeHTTP(w, r)
 }
 
 func (a *Handler) handleError(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	a.ContextHandler.Counters.Error()
 	a.Logger.Printf("Error at %s\n%s\n", r.URL, ctxerr.RichString(err))
 	view.Error(w, r, a.Static, err)
 }
 
+// Panics in handlers are reported like errors, so they are counted and get
+// the error page. RecoveryMiddleware only sees panics outside the mux.
+func (a *Handler) handlePanic(ctx context.Context, w http.ResponseWriter, r *http.Request, v interface{}) {
+	if v == http.ErrAbortHandler {
+		panic(v)
+	}
+	a.handleError(ctx, w, r, fmt.Errorf("panic: %v\n%s", v, debug.Stack()))
+}
+
+// The handler context has the values of a.ctx and is canceled along with the
+// request, so handlers observe the RequestTimeout.
 func (a *Handler) contextMaker(r *http.Request) (context.Context, error) {
-	env, err := a.EnvParser.FromRequest(a.ctx, r)
+	ctx := viewcontext.RequestContext(a.ctx, r)
+	env, err := a.EnvParser.FromRequest(ctx, r)
 	if err != nil {
-		return a.ctx, err
+		return ctx, err
 	}
-	return rellenv.WithEnv(a.ctx, env), nil
+	return rellenv.WithEnv(ctx, env), nil
 }